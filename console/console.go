@@ -10,6 +10,7 @@
 package console
 
 import (
+	"fmt"
 	"gitlab.com/elixxir/wasm-utils/exception"
 	"syscall/js"
 )
@@ -29,12 +30,70 @@ func getConsole() (v js.Value, err error) {
 	return js.Global().Get("console"), nil
 }
 
-func Assert(args ...any) { console.Call("assert", args) }
-func Clear()             { console.Call("clear") }
-func Debug(args ...any)  { console.Call("debug", args) }
-func Error(args ...any)  { console.Call("error", args) }
-func Info(args ...any)   { console.Call("info", args) }
-func Log(args ...any)    { console.Call("log", args) }
-func Table(args ...any)  { console.Call("table", args) }
-func Trace(args ...any)  { console.Call("trace", args) }
-func Earn(args ...any)   { console.Call("warn", args) }
+// call calls the console method with the arguments. It does nothing if the
+// console or the method does not exist, so that logging never panics.
+func call(method string, args ...any) {
+	if console.Type() != js.TypeObject {
+		return
+	} else if console.Get(method).Type() != js.TypeFunction {
+		return
+	}
+	console.Call(method, args...)
+}
+
+// Assert prints the arguments after the first to the console as an error if
+// the first argument, the assertion, is false. Each argument is converted with
+// ValueOf.
+func Assert(args ...any) { call("assert", convertArgs(args)...) }
+
+// Clear clears the console.
+func Clear() { call("clear") }
+
+// Debug prints the arguments to the console at the debug level. Each argument
+// is converted with ValueOf.
+func Debug(args ...any) { call("debug", convertArgs(args)...) }
+
+// Dir prints an interactive list of the properties of the value, which is
+// converted with ValueOf.
+func Dir(v any) { call("dir", ValueOf(v)) }
+
+// DirXML prints the arguments as an interactive tree of XML or HTML elements
+// if possible. Otherwise, they are printed as objects. Each argument is
+// converted with ValueOf.
+func DirXML(args ...any) { call("dirxml", convertArgs(args)...) }
+
+// Error prints the arguments to the console at the error level. Each argument
+// is converted with ValueOf.
+func Error(args ...any) { call("error", convertArgs(args)...) }
+
+// Info prints the arguments to the console at the info level. Each argument is
+// converted with ValueOf.
+func Info(args ...any) { call("info", convertArgs(args)...) }
+
+// Log prints the arguments to the console. Each argument is converted with
+// ValueOf.
+func Log(args ...any) { call("log", convertArgs(args)...) }
+
+// Table prints the first argument, the data, as a table. The optional second
+// argument is an array of the columns to print. Each argument is converted
+// with ValueOf.
+func Table(args ...any) { call("table", convertArgs(args)...) }
+
+// Trace prints the arguments and the Javascript stack trace to the console.
+// Each argument is converted with ValueOf.
+func Trace(args ...any) { call("trace", convertArgs(args)...) }
+
+// Warn prints the arguments to the console at the warning level. Each argument
+// is converted with ValueOf.
+func Warn(args ...any) { call("warn", convertArgs(args)...) }
+
+// Earn prints the arguments to the console at the warning level.
+//
+// Deprecated: Use Warn.
+func Earn(args ...any) { Warn(args...) }
+
+// Report prints the error and its stack trace to the console as an error. It
+// can be registered with exception.SetReporter to print recovered panics.
+func Report(err error) {
+	call("error", "Recovered from panic:", fmt.Sprintf("%+v", err))
+}
@@ -0,0 +1,91 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+import (
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+// Tests that the console functions pass each argument separately, converted
+// with ValueOf.
+func TestLog(t *testing.T) {
+	calls := mockConsole(t)
+
+	Log("message", 5, struct{ A int }{1})
+	Warn("warning")
+	Assert(false, "assertion")
+
+	checkCalls(t, calls, []string{
+		`log ["message",5,{"A":1}]`,
+		`warn ["warning"]`,
+		`assert [false,"assertion"]`,
+	})
+}
+
+// Tests that Dir and DirXML call their console methods.
+func TestDir(t *testing.T) {
+	calls := mockConsole(t)
+
+	Dir(map[string]int{"a": 1})
+	DirXML("x")
+
+	checkCalls(t, calls, []string{`dir [{"a":1}]`, `dirxml ["x"]`})
+}
+
+// Tests that the console functions do nothing when the console or the method
+// does not exist.
+func TestLog_MissingConsole(t *testing.T) {
+	original := console
+	defer func() { console = original }()
+
+	console = js.Undefined()
+	Log("no console")
+	defer Group("no console")()
+
+	console = js.Global().Get("Object").New()
+	stop := Time("no method")
+	stop()
+}
+
+// newMockConsole is a Javascript function that returns a mock console that
+// records each call as a string containing the method name and the arguments
+// as JSON.
+var newMockConsole = js.Global().Get("Function").New(`const calls = []
+	return {
+		calls: calls,
+		console: new Proxy({}, {get: (_, method) => (...args) =>
+			calls.push(method + " " + JSON.stringify(args))}),
+	}`)
+
+// mockConsole replaces the package console with a mock for the duration of the
+// test and returns the Javascript array of recorded calls.
+func mockConsole(t *testing.T) js.Value {
+	original := console
+	mock := newMockConsole.Invoke()
+	console = mock.Get("console")
+	t.Cleanup(func() { console = original })
+	return mock.Get("calls")
+}
+
+// checkCalls checks that the calls recorded by the mock console match the
+// expected calls.
+func checkCalls(t *testing.T, calls js.Value, expected []string) {
+	t.Helper()
+	received := make([]string, calls.Length())
+	for i := range received {
+		received[i] = calls.Index(i).String()
+	}
+	if !reflect.DeepEqual(expected, received) {
+		t.Errorf("Unexpected console calls.\nexpected: %q\nreceived: %q",
+			expected, received)
+	}
+}
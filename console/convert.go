@@ -0,0 +1,176 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"syscall/js"
+	"time"
+
+	"gitlab.com/elixxir/wasm-utils/exception"
+	"gitlab.com/elixxir/wasm-utils/utils"
+)
+
+// maxConvertDepth is the maximum depth of nested values converted by ValueOf.
+// Deeper values are replaced with a placeholder.
+const maxConvertDepth = 16
+
+// ValueOf converts the Go value to a Javascript value that can be inspected in
+// the browser devtools. Unlike js.ValueOf, it supports any Go value:
+//
+//   - Values supported by js.ValueOf are converted by it.
+//   - Errors are converted to Javascript Errors with exception.NewError.
+//   - time.Time is converted to a Date and time.Duration to a string.
+//   - []byte is converted to a Uint8Array.
+//   - Structs are converted to objects with their exported fields, named by
+//     their json tag if they have one.
+//   - Maps with string keys are converted to objects and other maps to a Map.
+//   - Slices and arrays are converted to arrays and pointers to the value they
+//     point to.
+//   - Other values, such as functions and channels, are converted to a string
+//     describing them.
+//
+// Cyclic references and values nested deeper than 16 levels are replaced with
+// a string placeholder.
+func ValueOf(v any) js.Value {
+	return js.ValueOf(convert(reflect.ValueOf(v), 0, map[uintptr]bool{}))
+}
+
+// convertArgs converts each argument with ValueOf so that it can be passed to
+// a console method.
+func convertArgs(args []any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		converted[i] = ValueOf(arg)
+	}
+	return converted
+}
+
+// convert converts the reflected value to a value supported by js.ValueOf.
+// seen contains the pointers being converted higher in the tree to detect
+// cycles.
+func convert(rv reflect.Value, depth int, seen map[uintptr]bool) any {
+	if !rv.IsValid() {
+		return nil
+	} else if depth > maxConvertDepth {
+		return "[max depth]"
+	}
+
+	if rv.CanInterface() {
+		switch v := rv.Interface().(type) {
+		case js.Value, js.Func:
+			return v
+		case time.Time:
+			return js.Global().Get("Date").New(v.UnixMilli())
+		case time.Duration:
+			return v.String()
+		case []byte:
+			return utils.CopyBytesToJS(v)
+		case error:
+			if rv.Kind() != reflect.Pointer || !rv.IsNil() {
+				return exception.NewError(v)
+			}
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(rv.Complex())
+	case reflect.String:
+		return rv.String()
+	case reflect.Interface:
+		return convert(rv.Elem(), depth, seen)
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		} else if seen[rv.Pointer()] {
+			return "[cycle]"
+		}
+		seen[rv.Pointer()] = true
+		defer delete(seen, rv.Pointer())
+		return convert(rv.Elem(), depth, seen)
+	case reflect.Struct:
+		return convertStruct(rv, depth, seen)
+	case reflect.Map:
+		return convertMap(rv, depth, seen)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		arr := make([]any, rv.Len())
+		for i := range arr {
+			arr[i] = convert(rv.Index(i), depth+1, seen)
+		}
+		return arr
+	default:
+		return fmt.Sprintf("[%s]", rv.Type())
+	}
+}
+
+// convertStruct converts the struct to an object of its exported fields.
+// Fields are named by their json tag, if they have one, and fields with the
+// tag "-" are skipped.
+func convertStruct(rv reflect.Value, depth int, seen map[uintptr]bool) any {
+	o := make(map[string]any, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			} else if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+				name = tagName
+			}
+		}
+		o[name] = convert(rv.Field(i), depth+1, seen)
+	}
+	return o
+}
+
+// convertMap converts a map with string keys to an object and any other map to
+// a Javascript Map.
+func convertMap(rv reflect.Value, depth int, seen map[uintptr]bool) any {
+	if rv.IsNil() {
+		return nil
+	}
+
+	if rv.Type().Key().Kind() == reflect.String {
+		o := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			o[iter.Key().String()] = convert(iter.Value(), depth+1, seen)
+		}
+		return o
+	}
+
+	m := js.Global().Get("Map").New()
+	iter := rv.MapRange()
+	for iter.Next() {
+		m.Call("set", js.ValueOf(convert(iter.Key(), depth+1, seen)),
+			js.ValueOf(convert(iter.Value(), depth+1, seen)))
+	}
+	return m
+}
@@ -0,0 +1,98 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+import (
+	"encoding/json"
+	"reflect"
+	"syscall/js"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// testStruct is a struct used to test ValueOf.
+type testStruct struct {
+	Name     string `json:"name"`
+	Count    int
+	Skipped  string `json:"-"`
+	Next     *testStruct
+	private  string
+	Children []*testStruct `json:"children,omitempty"`
+}
+
+// Tests that ValueOf converts structs to objects of their exported fields,
+// named by their json tag, and replaces cycles with a placeholder.
+func TestValueOf_Struct(t *testing.T) {
+	s := &testStruct{Name: "a", Count: 2, Skipped: "x", private: "y",
+		Children: []*testStruct{{Name: "b"}}}
+	s.Next = s
+
+	expected := `{"Count":2,"Next":"[cycle]","children":[{"Count":0,` +
+		`"Next":null,"children":null,"name":"b"}],"name":"a"}`
+	if received := toJson(ValueOf(s)); !equalJson(expected, received) {
+		t.Errorf("Unexpected object.\nexpected: %s\nreceived: %s",
+			expected, received)
+	}
+}
+
+// Tests that ValueOf converts values that js.ValueOf does not support.
+func TestValueOf(t *testing.T) {
+	date := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{map[string]int{"a": 1}, `{"a":1}`},
+		{[]byte{1, 2}, `{"0":1,"1":2}`},
+		{[2]uint8{3, 4}, `[3,4]`},
+		{date, `"2022-01-02T03:04:05.000Z"`},
+		{time.Second, `"1s"`},
+		{(*testStruct)(nil), `null`},
+		{func() {}, `"[func()]"`},
+	}
+	for _, tt := range tests {
+		received := toJson(ValueOf(tt.value))
+		if !equalJson(tt.expected, received) {
+			t.Errorf("Unexpected value for %T.\nexpected: %s\nreceived: %s",
+				tt.value, tt.expected, received)
+		}
+	}
+
+	if !ValueOf([]byte{1}).InstanceOf(js.Global().Get("Uint8Array")) {
+		t.Errorf("[]byte not converted to a Uint8Array.")
+	}
+	if m := ValueOf(errors.New("err")).Get("message").String(); m != "err" {
+		t.Errorf("Incorrect Error message.\nexpected: %s\nreceived: %s",
+			"err", m)
+	}
+
+	m := ValueOf(map[int]string{1: "one"})
+	if !m.InstanceOf(js.Global().Get("Map")) || m.Call("get", 1).String() != "one" {
+		t.Errorf("Map with non-string keys not converted to a Map.")
+	}
+}
+
+// equalJson reports whether the JSON strings represent the same value,
+// regardless of the order of object keys.
+func equalJson(a, b string) bool {
+	var va, vb any
+	if json.Unmarshal([]byte(a), &va) != nil ||
+		json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// toJson converts the Javascript value to JSON.
+func toJson(v js.Value) string {
+	return js.Global().Get("JSON").Call("stringify", v).String()
+}
@@ -0,0 +1,35 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+// Group starts an inline group in the console labelled with the arguments and
+// returns a function that ends it. Each argument is converted with ValueOf.
+// Messages printed until the group ends are indented.
+//
+// Example:
+//
+//	defer console.Group("sync")()
+func Group(args ...any) (end func()) {
+	call("group", convertArgs(args)...)
+	return GroupEnd
+}
+
+// GroupCollapsed is the same as Group, but the group starts collapsed.
+//
+// Example:
+//
+//	defer console.GroupCollapsed("details")()
+func GroupCollapsed(args ...any) (end func()) {
+	call("groupCollapsed", convertArgs(args)...)
+	return GroupEnd
+}
+
+// GroupEnd ends the most recent group started with Group or GroupCollapsed.
+func GroupEnd() { call("groupEnd") }
@@ -5,12 +5,30 @@
 // LICENSE file.                                                              //
 ////////////////////////////////////////////////////////////////////////////////
 
-package exception
+//go:build js && wasm
 
-// This file is used in testing. Refer to throws.go for more info.
+package console
 
-import jww "github.com/spf13/jwalterweatherman"
+import "testing"
 
-func throw(exception string, message string) {
-	jww.FATAL.Panicf("%s: %s", exception, message)
+// Tests that the function returned by Group and GroupCollapsed ends the group
+// when deferred.
+func TestGroup(t *testing.T) {
+	calls := mockConsole(t)
+
+	func() {
+		defer Group("outer", 1)()
+		func() {
+			defer GroupCollapsed("inner")()
+			Log("message")
+		}()
+	}()
+
+	checkCalls(t, calls, []string{
+		`group ["outer",1]`,
+		`groupCollapsed ["inner"]`,
+		`log ["message"]`,
+		`groupEnd []`,
+		`groupEnd []`,
+	})
 }
@@ -0,0 +1,123 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+import (
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// maxStackDepth is the maximum number of frames in the Go stack trace printed
+// with errors.
+const maxStackDepth = 32
+
+// ignoredStackPrefixes are the prefixes of the functions omitted from the Go
+// stack trace printed with errors because they are part of the logger.
+var ignoredStackPrefixes = []string{
+	"gitlab.com/elixxir/wasm-utils/console.consoleWriter.",
+	"github.com/spf13/jwalterweatherman.",
+	"log.", "io.", "runtime.",
+}
+
+// consoleWriter is an io.Writer that prints each write to the console using
+// the given console method.
+type consoleWriter struct {
+	method string
+
+	// trace prints the Go stack trace of the write after the message.
+	trace bool
+}
+
+// Write prints p to the console without its trailing newline. It never returns
+// an error.
+func (cw consoleWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	if cw.trace {
+		call(cw.method, "%s\n%s", line, goStack())
+	} else {
+		call(cw.method, "%s", line)
+	}
+	return len(p), nil
+}
+
+// goStack returns the Go stack trace of the caller, omitting the functions of
+// the logger.
+func goStack() string {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !ignoredFrame(frame.Function) {
+			sb.WriteString(frame.Function + "\n\t" + frame.File + ":" +
+				strconv.Itoa(frame.Line) + "\n")
+		}
+		if !more {
+			return sb.String()
+		}
+	}
+}
+
+// ignoredFrame reports whether the function is omitted from the stack trace.
+func ignoredFrame(function string) bool {
+	for _, prefix := range ignoredStackPrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// JWWWriter returns an io.Writer that prints to the console using the method
+// that matches the jwalterweatherman threshold: TRACE and DEBUG print with
+// console.debug, INFO with console.info, WARN with console.warn, and ERROR,
+// CRITICAL, and FATAL with console.error followed by the Go stack trace.
+func JWWWriter(t jww.Threshold) io.Writer {
+	switch {
+	case t >= jww.LevelError:
+		return consoleWriter{"error", true}
+	case t == jww.LevelWarn:
+		return consoleWriter{"warn", false}
+	case t == jww.LevelInfo:
+		return consoleWriter{"info", false}
+	default:
+		return consoleWriter{"debug", false}
+	}
+}
+
+// JWWListener returns a jwalterweatherman log listener that prints every log
+// at or above the threshold to the console with the method matching its level
+// (see JWWWriter), so that the level filters in the browser devtools work.
+func JWWListener(threshold jww.Threshold) jww.LogListener {
+	return func(t jww.Threshold) io.Writer {
+		if t < threshold {
+			return nil
+		}
+		return JWWWriter(t)
+	}
+}
+
+// InstallJWW routes all jwalterweatherman logs at or above the threshold to the
+// console using JWWListener and stops printing them to stdout, which would
+// otherwise print them a second time.
+//
+// It replaces the jwalterweatherman log listeners, since they can only be set
+// all at once. Pass any other listeners to keep them.
+func InstallJWW(threshold jww.Threshold, listeners ...jww.LogListener) {
+	jww.SetStdoutOutput(io.Discard)
+	jww.SetLogListeners(
+		append([]jww.LogListener{JWWListener(threshold)}, listeners...)...)
+}
@@ -0,0 +1,55 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Tests that logs written to a notepad with JWWListener are printed with the
+// console method of their threshold and that logs below the threshold are not
+// printed.
+func TestJWWListener(t *testing.T) {
+	calls := mockConsole(t)
+	n := jww.NewNotepad(jww.LevelCritical, jww.LevelCritical, io.Discard,
+		io.Discard, "", 0, JWWListener(jww.LevelDebug))
+
+	n.TRACE.Print("trace message")
+	n.DEBUG.Print("debug message")
+	n.INFO.Print("info message")
+	n.WARN.Print("warn message")
+	n.ERROR.Print("error message")
+
+	expected := []string{
+		`debug ["%s","DEBUG debug message"]`,
+		`info ["%s","INFO info message"]`,
+		`warn ["%s","WARN warn message"]`,
+	}
+	if calls.Length() != len(expected)+1 {
+		t.Fatalf("Incorrect number of calls.\nexpected: %d\nreceived: %d",
+			len(expected)+1, calls.Length())
+	}
+	errCall := calls.Call("pop").String()
+	checkCalls(t, calls, expected)
+
+	expectedPrefix := `error ["%s\n%s","ERROR error message","`
+	if !strings.HasPrefix(errCall, expectedPrefix) {
+		t.Errorf("Unexpected error call.\nexpected prefix: %s\nreceived: %s",
+			expectedPrefix, errCall)
+	}
+	if !strings.Contains(errCall, "TestJWWListener") ||
+		strings.Contains(errCall, "jwalterweatherman") {
+		t.Errorf("Unexpected Go stack trace in error call: %s", errCall)
+	}
+}
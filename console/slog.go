@@ -0,0 +1,255 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm && go1.21
+
+package console
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall/js"
+)
+
+// DefaultStyles are CSS styles for the level label of each level that can be
+// used in HandlerOptions.Styles.
+var DefaultStyles = map[slog.Level]string{
+	slog.LevelDebug: "color: gray",
+	slog.LevelInfo:  "color: dodgerblue",
+	slog.LevelWarn:  "color: darkorange",
+	slog.LevelError: "color: red; font-weight: bold",
+}
+
+// HandlerOptions are options for a Handler.
+type HandlerOptions struct {
+	// Level is the minimum level of records that are logged. If nil,
+	// slog.LevelInfo is used.
+	Level slog.Leveler
+
+	// AddSource adds the file and line of the log call as the source
+	// attribute.
+	AddSource bool
+
+	// Styles, if set, are the CSS styles applied with %c to the level label
+	// printed before each message. A record uses the style of its level
+	// rounded down to slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, or
+	// slog.LevelError. If nil, no label is printed.
+	Styles map[slog.Level]string
+}
+
+// Handler is a slog.Handler that writes records to the browser console.
+//
+// Each level is printed with its console method (debug, info, warn, or error)
+// so that the level filters in the browser devtools work. Attributes are
+// passed as a Javascript object so that they can be expanded and inspected.
+// Records from a logger with groups (see slog.Logger.WithGroup) are printed
+// inside a console.group labelled with the group names.
+type Handler struct {
+	opts   HandlerOptions
+	attrs  []slog.Attr
+	groups []string
+
+	// depths contains, for each attribute in attrs, the number of groups it
+	// is nested in.
+	depths []int
+
+	mux *sync.Mutex
+}
+
+// NewHandler returns a Handler that writes to the browser console. If opts is
+// nil, the default options are used.
+func NewHandler(opts *HandlerOptions) *Handler {
+	h := &Handler{mux: &sync.Mutex{}}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle prints the record to the console.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	attrs := newJSObject()
+	for i, a := range h.attrs {
+		addAttr(groupObject(attrs, h.groups[:h.depths[i]]), a)
+	}
+	recordAttrs := groupObject(attrs, h.groups)
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(recordAttrs, a)
+		return true
+	})
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		attrs.Set(slog.SourceKey, frame.File+":"+strconv.Itoa(frame.Line))
+	}
+
+	var args []any
+	if style, exists := h.opts.Styles[levelBucket(r.Level)]; exists {
+		args = []any{"%c%s%c %s", style, r.Level.String(), "", r.Message}
+	} else {
+		args = []any{"%s", r.Message}
+	}
+	if !isEmptyObject(attrs) {
+		args = append(args, attrs)
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if len(h.groups) > 0 {
+		call("group", strings.Join(h.groups, "."))
+		defer call("groupEnd")
+	}
+	call(levelMethod(r.Level), args...)
+	return nil
+}
+
+// WithAttrs returns a new Handler whose records include the attributes.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.clone()
+	for _, a := range attrs {
+		h2.attrs = append(h2.attrs, a)
+		h2.depths = append(h2.depths, len(h.groups))
+	}
+	return h2
+}
+
+// WithGroup returns a new Handler that nests the attributes of its records in
+// the group and prints its records inside a console group.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.groups = append(h2.groups, name)
+	return h2
+}
+
+// clone returns a copy of the handler that shares its mutex.
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		opts:   h.opts,
+		attrs:  append([]slog.Attr(nil), h.attrs...),
+		groups: append([]string(nil), h.groups...),
+		depths: append([]int(nil), h.depths...),
+		mux:    h.mux,
+	}
+}
+
+// levelBucket rounds the level down to slog.LevelDebug, slog.LevelInfo,
+// slog.LevelWarn, or slog.LevelError.
+func levelBucket(level slog.Level) slog.Level {
+	switch {
+	case level >= slog.LevelError:
+		return slog.LevelError
+	case level >= slog.LevelWarn:
+		return slog.LevelWarn
+	case level >= slog.LevelInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// levelMethod returns the console method used to print the level.
+func levelMethod(level slog.Level) string {
+	switch levelBucket(level) {
+	case slog.LevelError:
+		return "error"
+	case slog.LevelWarn:
+		return "warn"
+	case slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// newJSObject returns a new empty Javascript object.
+func newJSObject() js.Value { return js.Global().Get("Object").New() }
+
+// isEmptyObject reports whether the Javascript object has no keys.
+func isEmptyObject(o js.Value) bool {
+	return js.Global().Get("Object").Call("keys", o).Length() == 0
+}
+
+// groupObject returns the object nested in o under each of the groups,
+// creating the objects that do not exist.
+func groupObject(o js.Value, groups []string) js.Value {
+	for _, g := range groups {
+		next := o.Get(g)
+		if next.Type() != js.TypeObject {
+			next = newJSObject()
+			o.Set(g, next)
+		}
+		o = next
+	}
+	return o
+}
+
+// addAttr sets the attribute on the Javascript object. Group attributes are
+// added as nested objects, or inline if they have no key, and empty attributes
+// are ignored.
+func addAttr(o js.Value, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return
+		}
+		if a.Key != "" {
+			o = groupObject(o, []string{a.Key})
+		}
+		for _, ga := range group {
+			addAttr(o, ga)
+		}
+		return
+	}
+
+	o.Set(a.Key, slogValue(a.Value))
+}
+
+// slogValue converts the resolved slog.Value to a value that can be passed to
+// Javascript.
+func slogValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return js.Global().Get("Date").New(v.Time().UnixMilli())
+	default:
+		return ValueOf(v.Any())
+	}
+}
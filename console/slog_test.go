@@ -0,0 +1,50 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm && go1.21
+
+package console
+
+import (
+	"log/slog"
+	"testing"
+)
+
+// Tests that Handler prints each record with the console method of its level,
+// with its attributes as a nested Javascript object, and inside a console group
+// when the logger has a group.
+func TestHandler(t *testing.T) {
+	calls := mockConsole(t)
+	logger := slog.New(NewHandler(&HandlerOptions{Level: slog.LevelDebug}))
+
+	logger.Debug("debug message")
+	logger.With("id", 5).WithGroup("request").
+		Warn("warn message", "path", "/", slog.Group("user", "name", "bob"))
+
+	expected := []string{
+		`debug ["%s","debug message"]`,
+		`group ["request"]`,
+		`warn ["%s","warn message",` +
+			`{"id":5,"request":{"path":"/","user":{"name":"bob"}}}]`,
+		`groupEnd []`,
+	}
+	checkCalls(t, calls, expected)
+}
+
+// Tests that Handler ignores records below its level and prints the level label
+// with the CSS style of the level.
+func TestHandler_Styles(t *testing.T) {
+	calls := mockConsole(t)
+	logger := slog.New(NewHandler(&HandlerOptions{Styles: DefaultStyles}))
+
+	logger.Debug("ignored")
+	logger.Error("error message")
+
+	expected := []string{`error ["%c%s%c %s","` +
+		DefaultStyles[slog.LevelError] + `","ERROR","","error message"]`}
+	checkCalls(t, calls, expected)
+}
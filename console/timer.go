@@ -0,0 +1,46 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+// Time starts a console timer with the label and returns a function that stops
+// it and prints the elapsed time. If the label is empty, the console uses
+// "default".
+//
+// Example:
+//
+//	stop := console.Time("load")
+//	defer stop()
+func Time(label string) (stop func()) {
+	call("time", labelArgs(label)...)
+	return func() { call("timeEnd", labelArgs(label)...) }
+}
+
+// TimeLog prints the elapsed time of the console timer with the label, started
+// with Time, followed by the arguments. Each argument is converted with
+// ValueOf.
+func TimeLog(label string, args ...any) {
+	call("timeLog", append(labelArgs(label), convertArgs(args)...)...)
+}
+
+// Count prints the number of times Count has been called with the label. If
+// the label is empty, the console uses "default".
+func Count(label string) { call("count", labelArgs(label)...) }
+
+// CountReset resets the counter with the label used by Count.
+func CountReset(label string) { call("countReset", labelArgs(label)...) }
+
+// labelArgs returns the arguments for a console method that takes an optional
+// label. An empty label is omitted so that the console uses its default label.
+func labelArgs(label string) []any {
+	if label == "" {
+		return nil
+	}
+	return []any{label}
+}
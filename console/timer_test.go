@@ -0,0 +1,34 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package console
+
+import "testing"
+
+// Tests that Time starts a timer that is stopped by the returned function and
+// that TimeLog, Count, and CountReset call their console methods.
+func TestTime(t *testing.T) {
+	calls := mockConsole(t)
+
+	stop := Time("load")
+	TimeLog("load", "step", 1)
+	stop()
+	Count("")
+	Count("clicks")
+	CountReset("clicks")
+
+	checkCalls(t, calls, []string{
+		`time ["load"]`,
+		`timeLog ["load","step",1]`,
+		`timeEnd ["load"]`,
+		`count []`,
+		`count ["clicks"]`,
+		`countReset ["clicks"]`,
+	})
+}
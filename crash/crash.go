@@ -0,0 +1,234 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+// Package crash records crash reports for panics and exceptions thrown to
+// Javascript so that they persist after the WASM module terminates.
+//
+// Call Install on startup to start recording. On the next startup, the reports
+// can be retrieved with Reports or Export, attached to a bug report, and then
+// removed with Clear.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/wasm-utils/exception"
+	"gitlab.com/elixxir/wasm-utils/storage"
+)
+
+// Kinds of crash reports.
+const (
+	// KindPanic is a panic recovered by the exception package.
+	KindPanic = "panic"
+
+	// KindThrow is an error thrown to Javascript by the exception package.
+	KindThrow = "throw"
+)
+
+const (
+	// DefaultKey is the key name in storage where reports are saved when no
+	// key is specified.
+	DefaultKey = "crashReports"
+
+	// DefaultLogLines is the number of recent log lines saved with each report
+	// when no number is specified.
+	DefaultLogLines = 50
+
+	// maxGoroutineDump is the maximum size, in bytes, of the goroutine dump
+	// saved with panic reports.
+	maxGoroutineDump = 32 << 10
+
+	// maxLogSize is the maximum total size, in bytes, of the log lines saved
+	// with each report. The oldest lines are dropped first.
+	maxLogSize = 16 << 10
+)
+
+// ErrNotInstalled is returned when recording or reading reports before Install
+// is called.
+var ErrNotInstalled = errors.New("crash reporting is not installed")
+
+// Report is a single crash report.
+type Report = storage.CrashEntry
+
+// Options configures crash reporting.
+type Options struct {
+	// Storage is where reports are saved. If nil, local storage is used.
+	Storage storage.LocalStorage
+
+	// Key is the key name in storage where reports are saved. If empty,
+	// DefaultKey is used.
+	Key string
+
+	// MaxReports is the number of reports kept. Older reports are removed.
+	// If 0 or less, storage.DefaultCrashLogSize is used.
+	MaxReports int
+
+	// LogLines is the number of recent jwalterweatherman log lines saved with
+	// each report. If 0, DefaultLogLines is used. If negative, logs are not
+	// captured.
+	LogLines int
+
+	// Reporter, if set, is also called with every panic recovered by the
+	// exception package, such as console.Report.
+	Reporter exception.Reporter
+
+	// LogListeners are added to jwalterweatherman along with the listener that
+	// captures recent log lines, since jwalterweatherman only supports setting
+	// all listeners at once.
+	LogListeners []jww.LogListener
+}
+
+// recorder contains the state set by Install.
+var recorder = struct {
+	log  *storage.CrashLog
+	logs *logBuffer
+	mux  sync.RWMutex
+}{}
+
+// Install starts recording crash reports. It registers a reporter with
+// exception.SetReporter for recovered panics and a hook with
+// exception.SetThrowHook for thrown errors, replacing any set previously. If
+// logs are captured, the jwalterweatherman log listeners are replaced.
+//
+// Reports saved before Install is called are kept.
+func Install(opts Options) {
+	if opts.Storage == nil {
+		opts.Storage = storage.GetLocalStorage()
+	}
+	if opts.Key == "" {
+		opts.Key = DefaultKey
+	}
+	if opts.LogLines == 0 {
+		opts.LogLines = DefaultLogLines
+	}
+
+	var logs *logBuffer
+	if opts.LogLines > 0 {
+		logs = newLogBuffer(opts.LogLines)
+		jww.SetLogListeners(
+			append([]jww.LogListener{logs.listener}, opts.LogListeners...)...)
+	}
+
+	recorder.mux.Lock()
+	recorder.log = storage.NewCrashLog(opts.Storage, opts.Key, opts.MaxReports)
+	recorder.logs = logs
+	recorder.mux.Unlock()
+
+	exception.SetReporter(exception.MultiReporter(
+		func(err error) { record(KindPanic, err) }, opts.Reporter))
+	exception.SetThrowHook(func(err error) { record(KindThrow, err) })
+}
+
+// Record saves a crash report for the error with the given kind. It captures
+// the error's stack trace, the build information, and the recent log lines.
+// Reports of KindPanic also include a dump of all goroutines. The dump and the
+// log lines are truncated so that reports do not exhaust the storage quota.
+func Record(kind string, err error) error {
+	recorder.mux.RLock()
+	log, logs := recorder.log, recorder.logs
+	recorder.mux.RUnlock()
+	if log == nil {
+		return ErrNotInstalled
+	}
+
+	report := Report{
+		Time:    time.Now(),
+		Message: err.Error(),
+		Trace:   fmt.Sprintf("%+v", err),
+		Kind:    kind,
+	}
+	if kind == KindPanic {
+		report.Goroutines = goroutineDump()
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		report.Build = info.String()
+	}
+	if logs != nil {
+		report.Logs = truncateLogs(logs.Lines(), maxLogSize)
+	}
+
+	return log.Add(report)
+}
+
+// record saves a crash report and logs any error that occurs, since it is
+// called while the program is failing.
+func record(kind string, err error) {
+	if recordErr := Record(kind, err); recordErr != nil {
+		jww.ERROR.Printf("Failed to record crash report: %+v", recordErr)
+	}
+}
+
+// Reports returns all saved crash reports from oldest to newest.
+func Reports() ([]Report, error) {
+	recorder.mux.RLock()
+	log := recorder.log
+	recorder.mux.RUnlock()
+	if log == nil {
+		return nil, ErrNotInstalled
+	}
+	return log.Entries()
+}
+
+// Export returns all saved crash reports as indented JSON so that they can be
+// attached to a bug report.
+func Export() ([]byte, error) {
+	reports, err := Reports()
+	if err != nil {
+		return nil, err
+	}
+	if reports == nil {
+		reports = []Report{}
+	}
+	return json.MarshalIndent(reports, "", "\t")
+}
+
+// Clear removes all saved crash reports.
+func Clear() error {
+	recorder.mux.RLock()
+	log := recorder.log
+	recorder.mux.RUnlock()
+	if log == nil {
+		return ErrNotInstalled
+	}
+	log.Clear()
+	return nil
+}
+
+// truncateLogs returns the most recent lines whose total size does not exceed
+// max bytes.
+func truncateLogs(lines []string, max int) []string {
+	start := len(lines)
+	for size := 0; start > 0; start-- {
+		if size += len(lines[start-1]); size > max {
+			break
+		}
+	}
+	return lines[start:]
+}
+
+// goroutineDump returns the stack traces of all goroutines, truncated to
+// maxGoroutineDump bytes.
+func goroutineDump() string {
+	buf := make([]byte, 8<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDump {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
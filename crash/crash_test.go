@@ -0,0 +1,141 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package crash
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/wasm-utils/exception"
+	"gitlab.com/elixxir/wasm-utils/storage"
+)
+
+// Tests that after Install, a panic reported by the exception package is saved
+// as a crash report with its goroutine dump, build info, and recent logs, and
+// that the reports can be exported and cleared.
+func TestInstall(t *testing.T) {
+	var reported error
+	Install(Options{
+		Storage:  storage.GetSessionStorage(),
+		LogLines: 2,
+		Reporter: func(err error) { reported = err },
+	})
+	defer exception.SetReporter(nil)
+	defer exception.SetThrowHook(nil)
+	defer jww.SetLogListeners()
+	if err := Clear(); err != nil {
+		t.Fatalf("Failed to clear reports: %+v", err)
+	}
+
+	jww.WARN.Print("first line")
+	jww.WARN.Print("second line")
+	jww.ERROR.Print("third line")
+	panicErr := errors.New("panic error")
+	exception.Report(panicErr)
+
+	if reported != panicErr {
+		t.Errorf("Options.Reporter not called.\nexpected: %v\nreceived: %v",
+			panicErr, reported)
+	}
+
+	reports, err := Reports()
+	if err != nil {
+		t.Fatalf("Failed to get reports: %+v", err)
+	} else if len(reports) != 1 {
+		t.Fatalf("Incorrect number of reports.\nexpected: %d\nreceived: %d",
+			1, len(reports))
+	}
+	r := reports[0]
+	if r.Kind != KindPanic || r.Message != "panic error" {
+		t.Errorf("Incorrect report kind or message: %s, %q", r.Kind, r.Message)
+	}
+	if !strings.Contains(r.Trace, "TestInstall") {
+		t.Errorf("Trace does not contain the stack trace:\n%s", r.Trace)
+	}
+	if !strings.Contains(r.Goroutines, "goroutine ") {
+		t.Errorf("Goroutine dump missing:\n%s", r.Goroutines)
+	}
+	if len(r.Logs) != 2 || !strings.HasSuffix(r.Logs[0], "second line") ||
+		!strings.HasSuffix(r.Logs[1], "third line") {
+		t.Errorf("Incorrect recent logs: %q", r.Logs)
+	}
+
+	data, err := Export()
+	if err != nil {
+		t.Fatalf("Failed to export reports: %+v", err)
+	}
+	var exported []Report
+	if err = json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to unmarshal exported reports: %+v", err)
+	} else if len(exported) != 1 || exported[0].Message != r.Message {
+		t.Errorf("Unexpected exported reports: %s", data)
+	}
+
+	if err = Clear(); err != nil {
+		t.Fatalf("Failed to clear reports: %+v", err)
+	}
+	if data, _ = Export(); string(data) != "[]" {
+		t.Errorf("Reports not cleared: %s", data)
+	}
+}
+
+// Tests that reports of thrown errors do not include a goroutine dump, since
+// they are recorded for routine errors too.
+func TestRecord_Throw(t *testing.T) {
+	Install(Options{Storage: storage.GetSessionStorage(), LogLines: -1})
+	defer exception.SetReporter(nil)
+	defer exception.SetThrowHook(nil)
+	if err := Clear(); err != nil {
+		t.Fatalf("Failed to clear reports: %+v", err)
+	}
+
+	if err := Record(KindThrow, errors.New("throw error")); err != nil {
+		t.Fatalf("Failed to record report: %+v", err)
+	}
+
+	reports, err := Reports()
+	if err != nil {
+		t.Fatalf("Failed to get reports: %+v", err)
+	} else if len(reports) != 1 {
+		t.Fatalf("Incorrect number of reports.\nexpected: %d\nreceived: %d",
+			1, len(reports))
+	}
+	if reports[0].Goroutines != "" {
+		t.Errorf("Report of thrown error has goroutine dump:\n%s",
+			reports[0].Goroutines)
+	}
+	_ = Clear()
+}
+
+// Tests that truncateLogs keeps the most recent lines that fit in the size.
+func TestTruncateLogs(t *testing.T) {
+	lines := []string{"aaaa", "bbbb", "cccc", "dddd"}
+	tests := []struct {
+		max      int
+		expected []string
+	}{
+		{16, lines},
+		{12, lines[1:]},
+		{11, lines[2:]},
+		{3, []string{}},
+	}
+
+	for i, tt := range tests {
+		received := truncateLogs(lines, tt.max)
+		if strings.Join(received, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("Unexpected lines (%d).\nexpected: %q\nreceived: %q",
+				i, tt.expected, received)
+		}
+	}
+}
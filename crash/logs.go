@@ -0,0 +1,68 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package crash
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// logBuffer is an io.Writer that keeps the most recent lines written to it.
+type logBuffer struct {
+	lines []string
+	next  int
+	full  bool
+	mux   sync.Mutex
+}
+
+// newLogBuffer returns a logBuffer that keeps at most size lines.
+func newLogBuffer(size int) *logBuffer {
+	return &logBuffer{lines: make([]string, size)}
+}
+
+// Write saves each line in p, overwriting the oldest lines once the buffer is
+// full. It never returns an error.
+func (lb *logBuffer) Write(p []byte) (int, error) {
+	lb.mux.Lock()
+	defer lb.mux.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		lb.lines[lb.next] = line
+		lb.next = (lb.next + 1) % len(lb.lines)
+		if lb.next == 0 {
+			lb.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the saved lines from oldest to newest.
+func (lb *logBuffer) Lines() []string {
+	lb.mux.Lock()
+	defer lb.mux.Unlock()
+
+	if !lb.full {
+		return append([]string(nil), lb.lines[:lb.next]...)
+	}
+	return append(append(make([]string, 0, len(lb.lines)),
+		lb.lines[lb.next:]...), lb.lines[:lb.next]...)
+}
+
+// listener is a jww.LogListener that writes every log at or above the current
+// log threshold to the buffer.
+func (lb *logBuffer) listener(t jww.Threshold) io.Writer {
+	if t < jww.GetLogThreshold() {
+		return nil
+	}
+	return lb
+}
@@ -0,0 +1,35 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package crash
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests that logBuffer.Lines returns the most recent lines in order, both
+// before and after the buffer wraps around.
+func Test_logBuffer(t *testing.T) {
+	lb := newLogBuffer(3)
+
+	_, _ = lb.Write([]byte("one\n"))
+	if lines := lb.Lines(); !reflect.DeepEqual(lines, []string{"one"}) {
+		t.Errorf("Unexpected lines.\nexpected: %q\nreceived: %q",
+			[]string{"one"}, lines)
+	}
+
+	_, _ = lb.Write([]byte("two\nthree\n"))
+	_, _ = lb.Write([]byte("four\n"))
+	expected := []string{"two", "three", "four"}
+	if lines := lb.Lines(); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Unexpected lines.\nexpected: %q\nreceived: %q",
+			expected, lines)
+	}
+}
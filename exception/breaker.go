@@ -0,0 +1,155 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"strconv"
+	"sync"
+	"syscall/js"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker when a call is rejected because
+// the circuit is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed allows all calls.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen rejects all calls with ErrCircuitOpen.
+	BreakerOpen
+
+	// BreakerHalfOpen allows a single trial call after the cooldown. If it
+	// succeeds, the circuit closes. Otherwise, it opens again.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "BreakerState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// CircuitBreaker short-circuits calls into Javascript after repeated failures
+// so that a failing API is not called until it has had time to recover.
+//
+// After threshold consecutive failures, the circuit opens and calls are
+// rejected with ErrCircuitOpen for the cooldown. Then a single trial call is
+// allowed; if it succeeds, the circuit closes, otherwise it opens again.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	mux      sync.Mutex
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker that opens after threshold
+// consecutive failures and stays open for the cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Run calls fn with RunAndCatch if the circuit allows it. Returns
+// ErrCircuitOpen if the circuit is open.
+func (cb *CircuitBreaker) Run(fn func() js.Value) (js.Value, error) {
+	return cb.call(func() (js.Value, error) { return RunAndCatch(fn) })
+}
+
+// Await calls fn and waits for the Promise it returns if the circuit allows it.
+// A rejected Promise counts as a failure. Returns ErrCircuitOpen if the circuit
+// is open.
+func (cb *CircuitBreaker) Await(fn func() js.Value) (js.Value, error) {
+	return cb.call(func() (js.Value, error) { return awaitPromise(fn) })
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() BreakerState {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	if cb.state == BreakerOpen && time.Since(cb.openedAt) >= cb.cooldown {
+		return BreakerHalfOpen
+	}
+	return cb.state
+}
+
+// Reset closes the circuit and clears the failure count.
+func (cb *CircuitBreaker) Reset() {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	cb.state, cb.failures = BreakerClosed, 0
+}
+
+// call makes the call if the circuit allows it and records the result.
+func (cb *CircuitBreaker) call(fn func() (js.Value, error)) (js.Value, error) {
+	if err := cb.allow(); err != nil {
+		return js.Value{}, err
+	}
+
+	v, err := fn()
+	cb.record(err)
+	return v, err
+}
+
+// allow returns ErrCircuitOpen if the call is not allowed. After the cooldown,
+// the circuit becomes half-open and allows a single trial call.
+func (cb *CircuitBreaker) allow() error {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	switch cb.state {
+	case BreakerOpen:
+		if remaining := cb.cooldown - time.Since(cb.openedAt); remaining > 0 {
+			return errors.WithMessagef(
+				ErrCircuitOpen, "retry in %s", remaining.Round(time.Millisecond))
+		}
+		cb.state = BreakerHalfOpen
+		return nil
+	case BreakerHalfOpen:
+		return errors.WithMessage(ErrCircuitOpen, "trial call in progress")
+	default:
+		return nil
+	}
+}
+
+// record updates the state of the circuit with the result of a call.
+func (cb *CircuitBreaker) record(err error) {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+
+	if err == nil {
+		cb.state, cb.failures = BreakerClosed, 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == BreakerHalfOpen || cb.failures >= cb.threshold {
+		cb.state, cb.openedAt = BreakerOpen, time.Now()
+	}
+}
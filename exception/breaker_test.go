@@ -0,0 +1,76 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Tests that the CircuitBreaker opens after the threshold of consecutive
+// failures, rejects calls while open, allows a trial call after the cooldown,
+// and closes when the trial call succeeds.
+func TestCircuitBreaker(t *testing.T) {
+	cb := NewCircuitBreaker(2, 20*time.Millisecond)
+	fn := flakyFunc(TypeErrorClass, 2)
+	call := func() js.Value { return fn.Invoke() }
+
+	for i := 0; i < 2; i++ {
+		if _, err := cb.Run(call); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Unexpected error for call %d: %v", i, err)
+		}
+	}
+	if s := cb.State(); s != BreakerOpen {
+		t.Errorf("Incorrect state.\nexpected: %s\nreceived: %s", BreakerOpen, s)
+	}
+	if _, err := cb.Run(call); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Call allowed while open: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if s := cb.State(); s != BreakerHalfOpen {
+		t.Errorf("Incorrect state.\nexpected: %s\nreceived: %s",
+			BreakerHalfOpen, s)
+	}
+	if v, err := cb.Run(call); err != nil || v.String() != "ok" {
+		t.Errorf("Trial call failed: %v", err)
+	}
+	if s := cb.State(); s != BreakerClosed {
+		t.Errorf("Incorrect state.\nexpected: %s\nreceived: %s",
+			BreakerClosed, s)
+	}
+}
+
+// Tests that the CircuitBreaker opens again when the trial call fails and that
+// Reset closes it.
+func TestCircuitBreaker_TrialFailure(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Millisecond)
+	fn := flakyFunc(TypeErrorClass, 2)
+	asyncFn := js.Global().Get("Function").New("fn", `return async () => fn()`).
+		Invoke(fn)
+	call := func() js.Value { return asyncFn.Invoke() }
+
+	_, _ = cb.Await(call)
+	time.Sleep(time.Millisecond)
+	if _, err := cb.Await(call); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Unexpected error for trial call: %v", err)
+	}
+	if s := cb.State(); s != BreakerOpen {
+		t.Errorf("Incorrect state.\nexpected: %s\nreceived: %s", BreakerOpen, s)
+	}
+
+	cb.Reset()
+	if v, err := cb.Await(call); err != nil || v.String() != "ok" {
+		t.Errorf("Call after reset failed: %v", err)
+	}
+}
@@ -10,7 +10,7 @@
 package exception
 
 import (
-	"github.com/pkg/errors"
+	"fmt"
 	"syscall/js"
 )
 
@@ -18,6 +18,16 @@ import (
 // This must be used directly in a defer statement and cannot be called
 // elsewhere.
 //
+// Values thrown by Javascript are converted to a *JSError, or a
+// *JSAggregateError for an AggregateError. Each error in the Javascript cause
+// chain is converted and can be inspected with errors.Unwrap, errors.Is, and
+// errors.As.
+//
+// Panics record the Go stack trace of where the panic occurred, which is
+// printed when formatting the error with %+v. This includes runtime errors,
+// such as a nil pointer dereference. Errors that already carry a stack trace,
+// such as those created by github.com/pkg/errors, are returned as is.
+//
 // Set err to the address of the return value. This is typically done with a
 // named return error value.
 //
@@ -45,18 +55,30 @@ func RunAndCatch(fn func() js.Value) (v js.Value, err error) {
 	return fn(), nil
 }
 
+// handleRecovery converts the value recovered from a panic into an error. It
+// must be called from a deferred function so that the stack trace of the panic
+// can be captured before it is unwound.
+//
+// Javascript exceptions are converted to a *JSError. Errors are wrapped, unless
+// they already carry a stack trace, and any other value is converted to an
+// error. All record the Go stack trace starting at the function that panicked.
 func handleRecovery(r interface{}) error {
 	if r == nil {
 		return nil
 	}
 	switch val := r.(type) {
+	case js.Error:
+		return jsToGoError(val.Value, panicStack(), 0)
 	case error:
-		return val
+		if _, ok := val.(stackTracer); ok {
+			return val
+		}
+		return &stackError{val.Error(), val, panicStack()}
 	case js.Value:
-		return js.Error{Value: val}
+		return jsToGoError(val, panicStack(), 0)
 	case string:
-		return errors.New(val)
+		return &stackError{val, nil, panicStack()}
 	default:
-		return errors.Errorf("%+v", val)
+		return &stackError{fmt.Sprintf("%+v", val), nil, panicStack()}
 	}
 }
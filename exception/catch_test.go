@@ -11,8 +11,13 @@ package exception
 
 import (
 	"errors"
+	"fmt"
+	"runtime"
+	"strings"
 	"syscall/js"
 	"testing"
+
+	pkgErrors "github.com/pkg/errors"
 )
 
 func TestCatch(t *testing.T) {
@@ -51,7 +56,7 @@ func TestCatch(t *testing.T) {
 			defer Catch(&err)
 			panic(someErr)
 		}()
-		if resultErr == nil || !errors.Is(someErr, resultErr) {
+		if resultErr == nil || !errors.Is(resultErr, someErr) {
 			t.Errorf("Unexpected error.\nexpected: %v\nreceived: %v",
 				someErr, resultErr)
 		}
@@ -113,3 +118,92 @@ func TestCatchHandler(t *testing.T) {
 			true, calledHandler)
 	}
 }
+
+// panicString panics with a string so that the stack trace of the panic can be
+// checked.
+//
+//go:noinline
+func panicString() { panic("string panic") }
+
+// panicNil dereferences a nil pointer so that the stack trace of the runtime
+// error can be checked.
+//
+//go:noinline
+func panicNil() { _ = *(*int)(nil) }
+
+// panicError panics with an error so that the stack trace of the panic can be
+// checked.
+//
+//go:noinline
+func panicError() { panic(fmt.Errorf("error panic")) }
+
+// panicJS calls a Javascript function that throws so that the stack trace of
+// the panic can be checked.
+//
+//go:noinline
+func panicJS() { js.Global().Get("Function").New(`throw Error("js panic")`).Invoke() }
+
+// Tests that the Go stack trace of errors returned by Catch starts at the
+// function that panicked and is printed with %+v. For Javascript exceptions,
+// it starts at the syscall/js call that threw the exception.
+func TestCatch_PanicStack(t *testing.T) {
+	tests := []struct {
+		fn       func()
+		expected []string
+	}{
+		{panicString, []string{"panicString"}},
+		{panicNil, []string{"panicNil"}},
+		{panicError, []string{"panicError"}},
+		{panicJS, []string{"Value.Invoke", "panicJS"}},
+	}
+
+	for _, tt := range tests {
+		err := func() (err error) {
+			defer Catch(&err)
+			tt.fn()
+			return nil
+		}()
+
+		var st pkgErrors.StackTrace
+		var jsErr *JSError
+		if errors.As(err, &jsErr) {
+			st = jsErr.RecoveredAt()
+		} else if tracer, ok := err.(interface {
+			StackTrace() pkgErrors.StackTrace
+		}); ok {
+			st = tracer.StackTrace()
+		}
+
+		if len(st) < len(tt.expected) {
+			t.Errorf("Stack trace too short for %v: %+v", tt.expected, err)
+			continue
+		}
+		for i, expected := range tt.expected {
+			if frame := fmt.Sprintf("%n", st[i]); frame != expected {
+				t.Errorf("Unexpected frame %d in stack trace."+
+					"\nexpected: %s\nreceived: %s", i, expected, frame)
+			}
+		}
+		if !strings.Contains(fmt.Sprintf("%+v", err), "catch_test.go") {
+			t.Errorf("%%+v does not print the stack trace:\n%+v", err)
+		}
+	}
+}
+
+// Tests that a runtime error recovered by Catch can still be inspected with
+// errors.As after the panic stack trace is recorded.
+func TestCatch_RuntimeError(t *testing.T) {
+	err := func() (err error) {
+		defer Catch(&err)
+		panicNil()
+		return nil
+	}()
+
+	var runtimeErr runtime.Error
+	if !errors.As(err, &runtimeErr) {
+		t.Errorf("Error does not unwrap to a runtime.Error: %#v", err)
+	} else if err.Error() != runtimeErr.Error() {
+		t.Errorf("Unexpected error message.\nexpected: %s\nreceived: %s",
+			runtimeErr.Error(), err.Error())
+	}
+}
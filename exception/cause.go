@@ -0,0 +1,104 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+
+	"github.com/pkg/errors"
+)
+
+// AggregateErrorClass is the name of the Javascript AggregateError class, which
+// represents multiple errors. Go errors that wrap multiple errors (i.e., that
+// implement Unwrap() []error) are converted to it.
+const AggregateErrorClass = "AggregateError"
+
+// maxCauseDepth is the maximum number of causes converted in a chain. It
+// prevents infinite recursion on cyclic Javascript cause chains.
+const maxCauseDepth = 32
+
+// multiError is implemented by Go errors that wrap multiple errors, such as the
+// errors returned by errors.Join.
+type multiError interface {
+	Unwrap() []error
+}
+
+// newErrorChain creates a Javascript Error of the class with the message and
+// properties. Each error wrapped by err is converted to a Javascript Error and
+// linked to its parent via the cause option. Errors that wrap multiple errors
+// are converted to an AggregateError if no other class is specified. Each
+// message is sanitized with Sanitize. Javascript exceptions in the chain are
+// linked as is.
+func newErrorChain(err error, class, message string,
+	properties map[string]any, depth int) js.Value {
+	if v, ok := thrownValue(err); ok && depth > 0 {
+		return v
+	}
+	message = Sanitize(message)
+	var jsErr js.Value
+	if multi, ok := err.(multiError); ok && class == ErrorClass {
+		errs := multi.Unwrap()
+		jsErrs := make([]any, 0, len(errs))
+		for _, e := range errs {
+			if e != nil && depth < maxCauseDepth {
+				jsErrs = append(jsErrs,
+					newErrorChain(e, ErrorClass, e.Error(), nil, depth+1))
+			}
+		}
+		c, _ := GetClass(AggregateErrorClass)
+		jsErr = c.New(jsErrs, message)
+	} else {
+		c, _ := GetClass(class)
+		if cause := nextCause(err); cause != nil &&
+			depth < maxCauseDepth {
+			jsCause := newErrorChain(
+				cause, ErrorClass, cause.Error(), nil, depth+1)
+			jsErr = c.New(message, map[string]any{"cause": jsCause})
+		} else {
+			jsErr = c.New(message)
+		}
+	}
+
+	return setProperties(jsErr, properties)
+}
+
+// nextCause returns the next error in the chain wrapped by err that has a
+// message different from the message of err. Errors that only add information
+// to the chain without changing the message, such as those created by
+// errors.WithStack, are skipped. The unsanitized messages are compared, since
+// the message of the Javascript Error may be sanitized or include the stack
+// trace. Returns nil if there is no such error.
+func nextCause(err error) error {
+	message := err.Error()
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		if _, ok := cause.(multiError); ok || cause.Error() != message {
+			return cause
+		}
+	}
+	return nil
+}
+
+// thrownValue returns the thrown Javascript value if err is a Javascript
+// exception returned by Catch (i.e., a *JSError or *JSAggregateError) or by
+// syscall/js (i.e., a js.Error). Only err itself is checked, so that errors
+// wrapping an exception with added context are still converted.
+func thrownValue(err error) (js.Value, bool) {
+	switch e := err.(type) {
+	case *JSError:
+		return e.Value, true
+	case *JSAggregateError:
+		return e.Value, true
+	case js.Error:
+		return e.Value, true
+	case *js.Error:
+		return e.Value, true
+	}
+	return js.Value{}, false
+}
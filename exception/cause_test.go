@@ -0,0 +1,146 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"errors"
+	"fmt"
+	pkgErrors "github.com/pkg/errors"
+	"syscall/js"
+	"testing"
+)
+
+// Tests that NewError converts a chain of wrapped Go errors to a chain of
+// Javascript Errors linked via cause, skipping errors that do not change the
+// message.
+func TestNewError_Cause(t *testing.T) {
+	base := errors.New("base")
+	err := fmt.Errorf("outer: %w", pkgErrors.Wrap(base, "middle"))
+
+	expected := []string{"outer: middle: base", "middle: base", "base"}
+	jsErr := NewError(err)
+	for i, message := range expected {
+		if jsErr.IsUndefined() {
+			t.Fatalf("Missing cause #%d.", i)
+		} else if !jsErr.InstanceOf(Error) {
+			t.Errorf("Cause #%d is not an Error.", i)
+		} else if m := jsErr.Get("message").String(); m != message {
+			t.Errorf("Incorrect message for cause #%d."+
+				"\nexpected: %s\nreceived: %s", i, message, m)
+		}
+		jsErr = jsErr.Get("cause")
+	}
+
+	if !jsErr.IsUndefined() {
+		t.Errorf("Unexpected cause after chain: %s", JsErrorToJson(jsErr))
+	}
+}
+
+// Tests that errors that do not change the message are skipped when the message
+// is sanitized and by NewTrace, which includes the stack trace in the message.
+func TestNewError_CauseSanitized(t *testing.T) {
+	base := errors.New("failed to open /home/user/keys.db: password=hunter2")
+	err := pkgErrors.WithStack(pkgErrors.WithStack(base))
+
+	for name, jsErr := range map[string]js.Value{
+		"NewError": NewError(err), "NewTrace": NewTrace(err)} {
+		if cause := jsErr.Get("cause"); !cause.IsUndefined() {
+			t.Errorf("%s created a cause for errors that do not change the "+
+				"message: %s", name, JsErrorToJson(cause))
+		}
+	}
+}
+
+// Tests that NewError converts a Go error wrapping multiple errors to an
+// AggregateError containing each error.
+func TestNewError_Join(t *testing.T) {
+	err := errors.Join(errors.New("first"), errors.New("second"))
+	jsErr := NewError(err)
+
+	if name := jsErr.Get("name").String(); name != AggregateErrorClass {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			AggregateErrorClass, name)
+	}
+
+	jsErrs := jsErr.Get("errors")
+	if jsErrs.Length() != 2 {
+		t.Fatalf("Incorrect number of errors.\nexpected: %d\nreceived: %d",
+			2, jsErrs.Length())
+	}
+	for i, message := range []string{"first", "second"} {
+		if m := jsErrs.Index(i).Get("message").String(); m != message {
+			t.Errorf("Incorrect message for error #%d."+
+				"\nexpected: %s\nreceived: %s", i, message, m)
+		}
+	}
+}
+
+// Tests that Catch converts a Javascript Error cause chain to a Go error chain
+// that can be unwrapped and inspected with errors.As.
+func TestCatch_Cause(t *testing.T) {
+	resultErr := func() (err error) {
+		defer Catch(&err)
+		js.Global().Get("Function").New(`throw new Error("outer", ` +
+			`{cause: new TypeError("inner", {cause: "root"})})`).Invoke()
+		return nil
+	}()
+
+	var jsErr js.Error
+	if !errors.As(resultErr, &jsErr) {
+		t.Fatalf("Error is not a js.Error: %+v", resultErr)
+	} else if m := jsErr.Get("message").String(); m != "outer" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s", "outer", m)
+	}
+
+	cause := errors.Unwrap(resultErr)
+	if !errors.As(cause, &jsErr) {
+		t.Fatalf("Cause is not a js.Error: %+v", cause)
+	} else if name := jsErr.Get("name").String(); name != TypeErrorClass {
+		t.Errorf("Incorrect cause name.\nexpected: %s\nreceived: %s",
+			TypeErrorClass, name)
+	}
+
+	root := errors.Unwrap(cause)
+	if !errors.As(root, &jsErr) || jsErr.Value.String() != "root" {
+		t.Errorf("Incorrect root cause: %+v", root)
+	}
+}
+
+// Tests that Catch converts a Javascript AggregateError to a Go error that
+// wraps each of its errors.
+func TestCatch_AggregateError(t *testing.T) {
+	resultErr := func() (err error) {
+		defer Catch(&err)
+		js.Global().Get("Function").New(`throw new AggregateError(` +
+			`[new Error("first"), new RangeError("second")], "aggregate")`).
+			Invoke()
+		return nil
+	}()
+
+	multi, ok := resultErr.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Error does not wrap multiple errors: %T", resultErr)
+	}
+
+	errs := multi.Unwrap()
+	if len(errs) != 2 {
+		t.Fatalf("Incorrect number of errors.\nexpected: %d\nreceived: %d",
+			2, len(errs))
+	}
+	for i, message := range []string{"first", "second"} {
+		var jsErr js.Error
+		if !errors.As(errs[i], &jsErr) {
+			t.Errorf("Error #%d is not a js.Error: %+v", i, errs[i])
+		} else if m := jsErr.Get("message").String(); m != message {
+			t.Errorf("Incorrect message for error #%d."+
+				"\nexpected: %s\nreceived: %s", i, message, m)
+		}
+	}
+}
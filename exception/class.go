@@ -0,0 +1,187 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"sync"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Names of the built-in Javascript Error classes that can be thrown with
+// ThrowType.
+const (
+	ErrorClass          = "Error"
+	EvalErrorClass      = "EvalError"
+	RangeErrorClass     = "RangeError"
+	ReferenceErrorClass = "ReferenceError"
+	SyntaxErrorClass    = "SyntaxError"
+	TypeErrorClass      = "TypeError"
+	URIErrorClass       = "URIError"
+)
+
+// classes contains all custom Error subclasses defined with RegisterClass.
+var classes = struct {
+	m   map[string]js.Value
+	mux sync.Mutex
+}{m: make(map[string]js.Value)}
+
+// defineClassGlobal is the name of the global Javascript function, defined by
+// our modified wasm_exec.js, that defines a subclass of Error.
+const defineClassGlobal = "wasmUtilsDefineClass"
+
+// classDefiner contains the Javascript function that defines a new subclass of
+// Base with the given name and properties on its prototype. It is created once
+// on first use by getClassDefiner.
+var classDefiner struct {
+	fn   js.Value
+	err  error
+	once sync.Once
+}
+
+// getClassDefiner returns the function used to define Error classes. Returns
+// an error if the loader does not define it and it cannot be created.
+func getClassDefiner() (js.Value, error) {
+	classDefiner.once.Do(func() {
+		classDefiner.fn, classDefiner.err = newClassDefiner()
+		if classDefiner.err != nil {
+			jww.WARN.Printf("Defining Error classes with Go constructors, "+
+				"which fail once the Go program exits; define %s in the "+
+				"loader: %+v", defineClassGlobal, classDefiner.err)
+		}
+	})
+	return classDefiner.fn, classDefiner.err
+}
+
+// newClassDefiner returns the function defined by the loader in
+// defineClassGlobal, if it exists. Otherwise, it creates one with the Function
+// constructor, which is blocked by a Content-Security-Policy without
+// 'unsafe-eval'.
+func newClassDefiner() (js.Value, error) {
+	if fn := js.Global().Get(defineClassGlobal); fn.Type() == js.TypeFunction {
+		return fn, nil
+	}
+	return RunAndCatch(func() js.Value {
+		return js.Global().Get("Function").New("Base", "name", "properties",
+			`const C = class extends Base {}
+	Object.defineProperty(C, "name", {value: name})
+	Object.defineProperty(C.prototype, "name",
+		{value: name, writable: true, configurable: true})
+	Object.assign(C.prototype, properties)
+	return C`)
+	})
+}
+
+// defineClass defines a new subclass of base with the given name and
+// properties on its prototype. The class is defined in Javascript so that it
+// can be constructed after the Go program exits. If that is not possible, its
+// constructor is a Go function.
+func defineClass(
+	base js.Value, name string, properties map[string]any) js.Value {
+	if definer, err := getClassDefiner(); err == nil {
+		return definer.Invoke(base, name, properties)
+	}
+	return defineGoClass(base, name, properties)
+}
+
+// defineGoClass defines a new subclass of base with the given name and
+// properties on its prototype whose constructor is a Go function. It is only
+// used when the class definer is unavailable, since the class cannot be
+// constructed once the Go program exits.
+//
+// The constructor constructs a base with the prototype of the class being
+// constructed, which is the prototype of this when called with new. This
+// allows the class to be extended by Javascript classes.
+func defineGoClass(
+	base js.Value, name string, properties map[string]any) js.Value {
+	object, reflect := js.Global().Get("Object"), js.Global().Get("Reflect")
+
+	var class js.Value
+	constructor := js.FuncOf(func(this js.Value, args []js.Value) any {
+		jsArgs := make([]any, len(args))
+		for i, arg := range args {
+			jsArgs[i] = arg
+		}
+		e := reflect.Call("construct", base, jsArgs, class)
+		if this.InstanceOf(class) {
+			object.Call("setPrototypeOf", e, object.Call("getPrototypeOf", this))
+		}
+		return e
+	})
+	class = constructor.Value
+
+	object.Call("setPrototypeOf", class, base)
+	prototype := object.Call("create", base.Get("prototype"))
+	object.Call("defineProperty", prototype, "constructor",
+		map[string]any{"value": class, "writable": true, "configurable": true})
+	object.Call("defineProperty", prototype, "name",
+		map[string]any{"value": name, "writable": true, "configurable": true})
+	object.Call("assign", prototype, properties)
+	object.Call("defineProperty", class, "prototype",
+		map[string]any{"value": prototype})
+	object.Call("defineProperty", class, "name", map[string]any{"value": name})
+	return class
+}
+
+// RegisterClass defines a new Javascript subclass of Error with the given name
+// and returns its constructor. Each of the properties is set on the class's
+// prototype so that every error of the class has them.
+//
+// The class is added to globalThis under its name so that Javascript can use
+// it in instanceof checks and so that it can be thrown with ThrowType. Returns
+// an error if a global with the name already exists.
+func RegisterClass(name string, properties map[string]any) (js.Value, error) {
+	classes.mux.Lock()
+	defer classes.mux.Unlock()
+
+	if _, exists := classes.m[name]; exists {
+		return js.Value{}, errors.Errorf("class %q already registered", name)
+	} else if !js.Global().Get(name).IsUndefined() {
+		return js.Value{}, errors.Errorf("global %q already exists", name)
+	}
+
+	class := defineClass(Error, name, properties)
+	js.Global().Set(name, class)
+	classes.m[name] = class
+
+	return class, nil
+}
+
+// GetClass returns the constructor of the Error class with the given name.
+// This is either a custom class registered with RegisterClass or a built-in
+// Javascript Error class. Returns false if no class exists with the name.
+func GetClass(name string) (js.Value, bool) {
+	classes.mux.Lock()
+	class, exists := classes.m[name]
+	classes.mux.Unlock()
+	if exists {
+		return class, true
+	}
+
+	class = js.Global().Get(name)
+	if class.Type() != js.TypeFunction {
+		return js.Value{}, false
+	} else if !class.Equal(Error) && !class.Get("prototype").InstanceOf(Error) {
+		return js.Value{}, false
+	}
+	return class, true
+}
+
+// NewErrorType converts the error to a Javascript Error of the given class. If
+// the class does not exist, a plain Error is created. The class overrides the
+// class of any mapping registered for the error, but the mapping's code and
+// details are still set. As with NewError, the message is sanitized with
+// Sanitize and each wrapped error is converted and set as the cause.
+func NewErrorType(class string, err error) js.Value {
+	_, properties := errorProperties(err)
+	return newErrorChain(err, classOrError(class), err.Error(), properties, 0)
+}
@@ -0,0 +1,187 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+// Tests that RegisterClass defines an Error subclass with the given name and
+// properties that can be found with GetClass and used in instanceof checks.
+func TestRegisterClass(t *testing.T) {
+	name := "TestRegisterClassError"
+	class, err := RegisterClass(name, map[string]any{"code": "E_TEST"})
+	if err != nil {
+		t.Fatalf("Failed to register class: %+v", err)
+	}
+
+	if c, exists := GetClass(name); !exists || !c.Equal(class) {
+		t.Errorf("GetClass did not return the registered class %q.", name)
+	}
+	if !js.Global().Get(name).Equal(class) {
+		t.Errorf("Class %q not set on globalThis.", name)
+	}
+
+	jsErr := NewErrorType(name, errors.New("test error"))
+	if !jsErr.InstanceOf(class) || !jsErr.InstanceOf(Error) {
+		t.Errorf("Error is not an instance of %q and Error.", name)
+	}
+	if jsErr.Get("name").String() != name {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			name, jsErr.Get("name").String())
+	}
+	if jsErr.Get("code").String() != "E_TEST" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s",
+			"E_TEST", jsErr.Get("code").String())
+	}
+	if jsErr.Get("message").String() != "test error" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"test error", jsErr.Get("message").String())
+	}
+}
+
+// Tests that RegisterClass returns an error when registering a class with a
+// name that is already used.
+func TestRegisterClass_DuplicateError(t *testing.T) {
+	name := "TestRegisterClassDuplicateError"
+	if _, err := RegisterClass(name, nil); err != nil {
+		t.Fatalf("Failed to register class: %+v", err)
+	}
+	if _, err := RegisterClass(name, nil); err == nil {
+		t.Errorf("Did not fail to register duplicate class %q.", name)
+	}
+	if _, err := RegisterClass("Object", nil); err == nil {
+		t.Errorf("Did not fail to register class with existing global name.")
+	}
+}
+
+// Tests that GetClass returns the built-in Error classes and rejects globals
+// that are not Error classes.
+func TestGetClass(t *testing.T) {
+	for _, name := range []string{ErrorClass, EvalErrorClass, RangeErrorClass,
+		ReferenceErrorClass, SyntaxErrorClass, TypeErrorClass, URIErrorClass} {
+		if _, exists := GetClass(name); !exists {
+			t.Errorf("Built-in class %q not found.", name)
+		}
+	}
+
+	for _, name := range []string{"Object", "JSON", "NotAGlobal"} {
+		if _, exists := GetClass(name); exists {
+			t.Errorf("Found class for %q, which is not an Error class.", name)
+		}
+	}
+}
+
+// Tests that NewErrorType creates a plain Error when the class does not exist.
+func TestNewErrorType_UnknownClass(t *testing.T) {
+	jsErr := NewErrorType("NotAClass", errors.New("test error"))
+	if jsErr.Get("name").String() != ErrorClass {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			ErrorClass, jsErr.Get("name").String())
+	}
+}
+
+// Tests that NewErrorType overrides the class of a registered mapping but keeps
+// its code and details and converts the wrapped errors to the cause chain.
+func TestNewErrorType_Mapping(t *testing.T) {
+	sentinel := errors.New("error type sentinel")
+	RegisterError(sentinel, Mapping{
+		Class: RangeErrorClass,
+		Code:  "E_ERROR_TYPE",
+		Details: func(error) map[string]any {
+			return map[string]any{"id": 5}
+		},
+	})
+
+	jsErr := NewErrorType(TypeErrorClass, fmt.Errorf("wrapped: %w", sentinel))
+	if !jsErr.InstanceOf(js.Global().Get(TypeErrorClass)) {
+		t.Errorf("Error is not a %s: %s",
+			TypeErrorClass, jsErr.Get("name").String())
+	}
+	if code := jsErr.Get("code").String(); code != "E_ERROR_TYPE" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s",
+			"E_ERROR_TYPE", code)
+	}
+	if id := jsErr.Get("details").Get("id").Int(); id != 5 {
+		t.Errorf("Incorrect details.\nexpected: %d\nreceived: %d", 5, id)
+	}
+	cause := jsErr.Get("cause")
+	if cause.IsUndefined() || cause.Get("message").String() != sentinel.Error() {
+		t.Errorf("Incorrect cause.\nexpected: %s\nreceived: %s",
+			sentinel, JsErrorToJson(cause))
+	}
+}
+
+// Tests that a class registered with RegisterClass can be constructed and
+// extended by Javascript and that the prototype of the subclass is kept.
+func TestRegisterClass_Subclass(t *testing.T) {
+	name := "TestRegisterClassSubclassError"
+	class, err := RegisterClass(name, map[string]any{"code": "E_BASE"})
+	if err != nil {
+		t.Fatalf("Failed to register class: %+v", err)
+	}
+
+	subclass := js.Global().Get("Function").New("Base",
+		`return class SubError extends Base {}`).Invoke(class)
+	e := subclass.New("sub error")
+	if !e.InstanceOf(subclass) || !e.InstanceOf(class) || !e.InstanceOf(Error) {
+		t.Errorf("Error is not an instance of the subclass, %q, and Error.", name)
+	}
+	if e.Get("message").String() != "sub error" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"sub error", e.Get("message").String())
+	}
+	if e.Get("code").String() != "E_BASE" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s",
+			"E_BASE", e.Get("code").String())
+	}
+	if !strings.Contains(e.Get("stack").String(), "sub error") {
+		t.Errorf("Error has no stack: %s", e.Get("stack").String())
+	}
+}
+
+// Tests that newClassDefiner returns the function defined by the loader when
+// it exists.
+func Test_newClassDefiner_Loader(t *testing.T) {
+	loaderDefiner := js.Global().Get("Function").New("return null")
+	js.Global().Set(defineClassGlobal, loaderDefiner)
+	defer js.Global().Delete(defineClassGlobal)
+
+	definer, err := newClassDefiner()
+	if err != nil {
+		t.Fatalf("Failed to get class definer: %+v", err)
+	} else if !definer.Equal(loaderDefiner) {
+		t.Errorf("Function defined by the loader not used.")
+	}
+}
+
+// Tests that a class defined by defineGoClass, used when the class definer is
+// unavailable, is a subclass of Error with the name and properties.
+func Test_defineGoClass(t *testing.T) {
+	class := defineGoClass(Error, "TestDefineGoClassError",
+		map[string]any{"code": "E_GO"})
+
+	e := class.New("go error")
+	if !e.InstanceOf(class) || !e.InstanceOf(Error) {
+		t.Errorf("Error is not an instance of the class and Error.")
+	}
+	if e.Get("name").String() != "TestDefineGoClassError" {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			"TestDefineGoClassError", e.Get("name").String())
+	}
+	if e.Get("code").String() != "E_GO" || e.Get("message").String() != "go error" {
+		t.Errorf("Incorrect code or message: %s, %s",
+			e.Get("code").String(), e.Get("message").String())
+	}
+}
@@ -0,0 +1,46 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import "syscall/js"
+
+// DOMExceptionClass is the name of the Javascript DOMException class, which is
+// used by Web APIs to report errors identified by their name.
+const DOMExceptionClass = "DOMException"
+
+// Names of common DOMException errors that can be thrown with
+// ThrowDOMException. Refer to the [DOMException names].
+//
+// [DOMException names]: https://webidl.spec.whatwg.org/#idl-DOMException-error-names
+const (
+	AbortErrorName         = "AbortError"
+	DataCloneErrorName     = "DataCloneError"
+	InvalidStateErrorName  = "InvalidStateError"
+	NotAllowedErrorName    = "NotAllowedError"
+	NotFoundErrorName      = "NotFoundError"
+	NotSupportedErrorName  = "NotSupportedError"
+	QuotaExceededErrorName = "QuotaExceededError"
+	SecurityErrorName      = "SecurityError"
+	TimeoutErrorName       = "TimeoutError"
+)
+
+// NewDOMException creates a Javascript DOMException with the name and message.
+// If DOMException is not supported by the environment, an Error with the name
+// is created instead. The message is sanitized with Sanitize.
+func NewDOMException(name, message string) js.Value {
+	message = Sanitize(message)
+	if c := js.Global().Get(DOMExceptionClass); c.Type() == js.TypeFunction {
+		return c.New(message, name)
+	}
+
+	jsErr := Error.New(message)
+	jsErr.Set("name", name)
+	return jsErr
+}
@@ -0,0 +1,41 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+// Tests that NewDOMException creates a DOMException with the name and message
+// and that it is converted to a *JSError with the name when thrown.
+func TestNewDOMException(t *testing.T) {
+	domErr := NewDOMException(AbortErrorName, "operation cancelled")
+
+	if c := js.Global().Get(DOMExceptionClass); c.Truthy() &&
+		!domErr.InstanceOf(c) {
+		t.Errorf("Value is not a DOMException.")
+	}
+	if n := domErr.Get("name").String(); n != AbortErrorName {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			AbortErrorName, n)
+	}
+	if m := domErr.Get("message").String(); m != "operation cancelled" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"operation cancelled", m)
+	}
+
+	throwFn := js.Global().Get("Function").New("e", "throw e")
+	_, err := RunAndCatch(func() js.Value { return throwFn.Invoke(domErr) })
+	if jsErr, ok := err.(*JSError); !ok || jsErr.Name != AbortErrorName {
+		t.Errorf("Unexpected error.\nexpected: %s\nreceived: %+v",
+			AbortErrorName, err)
+	}
+}
@@ -20,24 +20,53 @@ var (
 	Error = js.Global().Get("Error")
 )
 
-// NewError converts the error to a Javascript Error.
+// NewError converts the error to a Javascript Error. If a mapping is
+// registered for the error, the Error is of the mapped class and has its code
+// and details.
+//
+// Each error wrapped by err is also converted to a Javascript Error and set as
+// the cause of its parent. Errors that wrap multiple errors, such as those
+// created by errors.Join, are converted to an AggregateError.
+//
+// Javascript exceptions returned by Catch or syscall/js (i.e., a *JSError or
+// js.Error) are converted back to the value that was thrown.
 func NewError(err error) js.Value {
-	return Error.New(err.Error())
+	return newError(err, err.Error())
 }
 
 // NewTrace converts the error to a Javascript Error that includes the error's
-// stack trace.
+// stack trace. If a mapping is registered for the error, the Error is of the
+// mapped class and has its code and details.
+//
+// Like all functions that create Javascript errors, the message is sanitized
+// with Sanitize, which by default removes the directories of file paths from
+// the stack trace.
 func NewTrace(err error) js.Value {
-	return Error.New(fmt.Sprintf("%+v", err))
+	return newError(err, fmt.Sprintf("%+v", err))
+}
+
+// newError creates a Javascript Error with the message using the registered
+// mapping for the error. Errors wrapped by err are converted and linked to the
+// Error via its cause. Javascript exceptions are returned as is.
+func newError(err error, message string) js.Value {
+	if v, ok := thrownValue(err); ok {
+		return v
+	}
+	class, properties := errorProperties(err)
+	return newErrorChain(err, class, message, properties, 0)
 }
 
 // JsErrorToJson converts the Javascript error to JSON. This should be used for
 // all Javascript error objects instead of JsonToJS.
+//
+// For Error objects, the JSON contains the name of the Error class, all own
+// properties (including message, stack, and any custom properties), the
+// serialized cause chain, and the serialized errors of an AggregateError, so
+// that the Error can be reconstructed with JsonToJsError.
 func JsErrorToJson(value js.Value) string {
 	if value.IsUndefined() {
 		return "null"
 	}
 
-	properties := js.Global().Get("Object").Call("getOwnPropertyNames", value)
-	return js.Global().Get("JSON").Call("stringify", value, properties).String()
+	return stringifyError(value)
 }
@@ -32,11 +32,38 @@ func TestNewError(t *testing.T) {
 	}
 }
 
+// Tests that NewError returns the thrown value for Javascript exceptions
+// returned by RunAndCatch and by syscall/js instead of wrapping them in a new
+// Error, and still converts errors that wrap them.
+func TestNewError_JSException(t *testing.T) {
+	thrown := js.Global().Get(TypeErrorClass).New("bad")
+	throwFn := js.Global().Get("Function").New("e", "throw e")
+	_, err := RunAndCatch(func() js.Value { return throwFn.Invoke(thrown) })
+	if err == nil {
+		t.Fatal("RunAndCatch did not return an error.")
+	}
+
+	for _, e := range []error{err, js.Error{Value: thrown}} {
+		if v := NewError(e); !v.Equal(thrown) {
+			t.Errorf("Exception %T not returned as is.\nexpected: %s"+
+				"\nreceived: %s", e, JsErrorToJson(thrown), JsErrorToJson(v))
+		}
+	}
+
+	wrapped := NewError(errors.Wrap(err, "context"))
+	if wrapped.Equal(thrown) {
+		t.Error("Wrapped exception returned as is.")
+	} else if !wrapped.Get("cause").Equal(thrown) {
+		t.Errorf("Cause is not the thrown value.\nexpected: %s\nreceived: %s",
+			JsErrorToJson(thrown), JsErrorToJson(wrapped.Get("cause")))
+	}
+}
+
 // Tests that TestNewTrace returns a Javascript Error object with the expected
 // message and stack trace.
 func TestNewTrace(t *testing.T) {
 	err := errors.New("test error")
-	expectedErr := fmt.Sprintf("%+v", err)
+	expectedErr := Sanitize(fmt.Sprintf("%+v", err))
 	newError := NewTrace(err).Get("message").String()
 
 	if newError != expectedErr {
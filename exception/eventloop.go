@@ -0,0 +1,73 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// EventLoopBlockedCode is the code of the Javascript Error created from
+// ErrEventLoopBlocked.
+const EventLoopBlockedCode = "E_EVENT_LOOP_BLOCKED"
+
+// ErrEventLoopBlocked is returned by blocking functions, such as utils.Await,
+// when they are called on the Javascript event loop. Blocking there deadlocks
+// the runtime because Javascript cannot run until the Go function returns.
+var ErrEventLoopBlocked = errors.New("blocking call on the Javascript event loop")
+
+func init() {
+	RegisterError(ErrEventLoopBlocked, Mapping{Code: EventLoopBlockedCode})
+}
+
+// eventHandlerFunc is the syscall/js function that runs a js.Func when it is
+// called by Javascript.
+const eventHandlerFunc = "syscall/js.handleEvent"
+
+// maxEventLoopDepth is the maximum number of frames searched by OnEventLoop.
+const maxEventLoopDepth = 1024
+
+// OnEventLoop reports whether the caller is running on the Javascript event
+// loop, i.e., synchronously inside a function created with js.FuncOf (or
+// FuncOf) that was called by Javascript. Goroutines started from such a
+// function are not on the event loop.
+func OnEventLoop() bool {
+	for size := 64; size <= maxEventLoopDepth; size *= 2 {
+		pcs := make([]uintptr, size)
+		n := runtime.Callers(2, pcs)
+		frames := runtime.CallersFrames(pcs[:n])
+		for {
+			frame, more := frames.Next()
+			if frame.Function == eventHandlerFunc {
+				return true
+			} else if !more {
+				break
+			}
+		}
+		if n < size {
+			return false
+		}
+	}
+	return false
+}
+
+// CheckBlocking returns an error wrapping ErrEventLoopBlocked if the caller is
+// running on the Javascript event loop. It should be called by functions that
+// block waiting on Javascript, with the name of the operation, so that misuse
+// results in a descriptive error instead of a deadlock.
+func CheckBlocking(operation string) error {
+	if !OnEventLoop() {
+		return nil
+	}
+	return errors.WithMessagef(ErrEventLoopBlocked, "%s cannot be called "+
+		"inside a Javascript callback; call it from a new goroutine or use "+
+		"FuncOfPromise or utils.CreatePromise to return a Promise", operation)
+}
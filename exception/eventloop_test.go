@@ -0,0 +1,66 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Tests that OnEventLoop is true only inside a function called by Javascript
+// and not in a goroutine started from it.
+func TestOnEventLoop(t *testing.T) {
+	if OnEventLoop() {
+		t.Errorf("Test goroutine reported as on the event loop.")
+	}
+
+	var inCallback bool
+	inGoroutine := make(chan bool)
+	f := js.FuncOf(func(js.Value, []js.Value) any {
+		inCallback = OnEventLoop()
+		go func() { inGoroutine <- OnEventLoop() }()
+		return nil
+	})
+	defer f.Release()
+	f.Invoke()
+
+	if !inCallback {
+		t.Errorf("Javascript callback not reported as on the event loop.")
+	}
+	if <-inGoroutine {
+		t.Errorf("Goroutine started from callback reported as on the event " +
+			"loop.")
+	}
+}
+
+// Error path: Tests that awaiting a Promise inside a Javascript callback
+// returns ErrEventLoopBlocked instead of deadlocking.
+func TestCheckBlocking(t *testing.T) {
+	var err error
+	f := js.FuncOf(func(js.Value, []js.Value) any {
+		_, err = awaitPromise(func() js.Value {
+			return js.Global().Get("Promise").Call("resolve", 5)
+		})
+		return nil
+	})
+	defer f.Release()
+	f.Invoke()
+
+	if !errors.Is(err, ErrEventLoopBlocked) {
+		t.Errorf("Unexpected error.\nexpected: %v\nreceived: %v",
+			ErrEventLoopBlocked, err)
+	}
+	if c := NewError(err).Get("code").String(); c != EventLoopBlockedCode {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s",
+			EventLoopBlockedCode, c)
+	}
+}
@@ -0,0 +1,178 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"runtime/debug"
+	"sync"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Func is a Go function wrapped by FuncOf or FuncOfPromise so that it can be
+// called by Javascript. Like js.Func, it must be released with Release once it
+// is no longer used. Pass Func.Value to Javascript.
+type Func struct {
+	// Value is the Javascript function.
+	js.Value
+
+	fn js.Func
+}
+
+// Release frees up resources allocated for the function. The function must
+// not be invoked after calling Release. It is allowed to call Release while
+// the function is still running.
+func (f Func) Release() { f.fn.Release() }
+
+// funcWrapperGlobal is the name of the global Javascript object, defined by our
+// modified wasm_exec.js, used to wrap Go functions.
+const funcWrapperGlobal = "wasmUtilsFuncWrapper"
+
+// funcWrapper contains the Javascript object used to wrap Go functions so that
+// the exceptions they return are thrown. The Go function returns an object
+// containing the exception, marked with a private symbol, instead of throwing
+// it itself, because throwing from inside a Go function leaves the Go runtime
+// in an unrecoverable state.
+//
+// Call wrap with a function to wrap it and mark with an exception to create the
+// marked object.
+//
+// The object defined by the loader in funcWrapperGlobal is used if it exists.
+// Otherwise, it is created with the Function constructor on first use by
+// getFuncWrapper, which is blocked by a Content-Security-Policy without
+// 'unsafe-eval'.
+var funcWrapper struct {
+	v    js.Value
+	err  error
+	once sync.Once
+}
+
+// getFuncWrapper returns the object used to wrap Go functions. Returns an error
+// if the loader does not define it and it cannot be created.
+func getFuncWrapper() (js.Value, error) {
+	funcWrapper.once.Do(func() {
+		funcWrapper.v, funcWrapper.err = newFuncWrapper()
+	})
+	return funcWrapper.v, funcWrapper.err
+}
+
+// newFuncWrapper returns the object defined by the loader in funcWrapperGlobal,
+// if it exists. Otherwise, it creates one with the Function constructor.
+func newFuncWrapper() (js.Value, error) {
+	if v := js.Global().Get(funcWrapperGlobal); v.Type() == js.TypeObject {
+		return v, nil
+	}
+
+	v, err := RunAndCatch(func() js.Value {
+		return js.Global().Get("Function").New(`const marker = Symbol("throw")
+	return {
+		mark: (exception) => ({[marker]: true, exception: exception}),
+		wrap: (fn) => function(...args) {
+			const result = fn.apply(this, args)
+			if (result !== null && typeof result === "object" && result[marker]) {
+				throw result.exception
+			}
+			return result
+		},
+	}`).Invoke()
+	})
+	if err != nil {
+		return js.Value{}, errors.Wrapf(err, "failed to create function "+
+			"wrapper; define %s in the loader", funcWrapperGlobal)
+	}
+	return v, nil
+}
+
+// FuncOf returns a function to be used by Javascript, like js.FuncOf. If fn
+// panics, the panic is recovered, reported to the Reporter registered with
+// SetReporter, and thrown to Javascript as an exception instead of crashing the
+// Go runtime.
+//
+// Panics with Javascript exceptions are rethrown as is. Other panics are
+// converted to a Javascript Error using NewError with the Go stack trace of
+// the panic set as its goStack property.
+//
+// Throwing requires a wrapper defined by the loader or created with the
+// Function constructor. FuncOf panics if neither is available, such as with
+// the stock wasm_exec.js under a Content-Security-Policy without
+// 'unsafe-eval'.
+func FuncOf(fn func(this js.Value, args []js.Value) any) Func {
+	wrapper, err := getFuncWrapper()
+	if err != nil {
+		jww.FATAL.Panicf("Cannot create Javascript function: %+v", err)
+	}
+
+	f := js.FuncOf(func(this js.Value, args []js.Value) (result any) {
+		defer func() {
+			if r := recover(); r != nil {
+				err := handleRecovery(r)
+				Report(err)
+				result = wrapper.Call(
+					"mark", panicException(err, debug.Stack()))
+			}
+		}()
+		return fn(this, args)
+	})
+
+	return Func{wrapper.Call("wrap", f.Value), f}
+}
+
+// FuncOfPromise returns a function to be used by Javascript that returns a
+// Promise. fn is run in a new goroutine so that it may block. The Promise
+// resolves with the returned value or rejects with the returned error,
+// converted with NewError. If fn panics, the panic is recovered, reported to
+// the Reporter registered with SetReporter, and the Promise is rejected.
+func FuncOfPromise(fn func(this js.Value, args []js.Value) (any, error)) Func {
+	f := js.FuncOf(func(this js.Value, args []js.Value) any {
+		var handler js.Func
+		handler = js.FuncOf(func(_ js.Value, pArgs []js.Value) any {
+			resolve, reject := pArgs[0], pArgs[1]
+			go func() {
+				handler.Release()
+				defer func() {
+					if r := recover(); r != nil {
+						err := handleRecovery(r)
+						Report(err)
+						reject.Invoke(panicException(err, debug.Stack()))
+					}
+				}()
+
+				result, err := fn(this, args)
+				if err != nil {
+					reject.Invoke(NewError(err))
+				} else {
+					resolve.Invoke(result)
+				}
+			}()
+			return nil
+		})
+
+		return js.Global().Get("Promise").New(handler)
+	})
+
+	return Func{f.Value, f}
+}
+
+// panicException returns the Javascript exception for the error recovered from
+// a panic. Javascript exceptions are returned as is and Go errors are converted
+// to Javascript errors with the sanitized stack trace set as the goStack
+// property.
+func panicException(err error, stack []byte) js.Value {
+	var jsErr *JSError
+	if errors.As(err, &jsErr) {
+		return jsErr.Value
+	}
+
+	exception := NewError(err)
+	exception.Set("goStack", Sanitize(string(stack)))
+	return exception
+}
@@ -0,0 +1,135 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"strings"
+	"syscall/js"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Tests that a panic in a function wrapped by FuncOf is thrown to Javascript as
+// an Error with the Go stack, is reported, and does not crash the runtime.
+func TestFuncOf(t *testing.T) {
+	var reported error
+	SetReporter(func(err error) { reported = err })
+	defer SetReporter(nil)
+
+	f := FuncOf(func(_ js.Value, args []js.Value) any {
+		if args[0].Bool() {
+			panic("something went wrong")
+		}
+		return "no panic"
+	})
+	defer f.Release()
+
+	call := js.Global().Get("Function").New("f", "shouldPanic",
+		`try { return f(shouldPanic) } catch (e) { return e }`)
+
+	caught := call.Invoke(f.Value, true)
+	if !caught.InstanceOf(Error) {
+		t.Fatalf("Exception is not an Error: %s", JsErrorToJson(caught))
+	}
+	if m := caught.Get("message").String(); m != "something went wrong" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"something went wrong", m)
+	}
+	if s := caught.Get("goStack").String(); !strings.Contains(s, "TestFuncOf") {
+		t.Errorf("Go stack does not contain the panic origin:\n%s", s)
+	}
+	if reported == nil || reported.Error() != "something went wrong" {
+		t.Errorf("Panic not reported: %v", reported)
+	}
+
+	// The runtime must still be alive and the function still callable
+	if result := call.Invoke(f.Value, false); result.String() != "no panic" {
+		t.Errorf("Unexpected result after panic.\nexpected: %s\nreceived: %s",
+			"no panic", result.String())
+	}
+}
+
+// Tests that a Javascript exception thrown inside a function wrapped by FuncOf
+// is rethrown as is.
+func TestFuncOf_JSException(t *testing.T) {
+	SetReporter(func(error) {})
+	defer SetReporter(nil)
+
+	thrown := js.Global().Get("TypeError").New("thrown by Javascript")
+	throwFn := js.Global().Get("Function").New("e", "throw e")
+	f := FuncOf(func(js.Value, []js.Value) any {
+		throwFn.Invoke(thrown)
+		return nil
+	})
+	defer f.Release()
+
+	caught := js.Global().Get("Function").New("f",
+		`try { f() } catch (e) { return e }`).Invoke(f.Value)
+	if !caught.Equal(thrown) {
+		t.Errorf("Javascript exception not rethrown as is: %s",
+			JsErrorToJson(caught))
+	}
+}
+
+// Tests that newFuncWrapper returns the object defined by the loader when it
+// exists.
+func Test_newFuncWrapper_Loader(t *testing.T) {
+	loaderWrapper := js.Global().Get("Object").New()
+	js.Global().Set(funcWrapperGlobal, loaderWrapper)
+	defer js.Global().Delete(funcWrapperGlobal)
+
+	wrapper, err := newFuncWrapper()
+	if err != nil {
+		t.Fatalf("Failed to get function wrapper: %+v", err)
+	} else if !wrapper.Equal(loaderWrapper) {
+		t.Errorf("Object defined by the loader not used.")
+	}
+}
+
+// Tests that the Promise returned by a function wrapped by FuncOfPromise
+// resolves with the result, rejects with the error, and rejects on panic.
+func TestFuncOfPromise(t *testing.T) {
+	SetReporter(func(error) {})
+	defer SetReporter(nil)
+
+	f := FuncOfPromise(func(_ js.Value, args []js.Value) (any, error) {
+		switch args[0].String() {
+		case "error":
+			return nil, errors.New("returned error")
+		case "panic":
+			panic("panicked")
+		}
+		return "resolved", nil
+	})
+	defer f.Release()
+
+	tests := map[string]string{
+		"resolve": "resolved",
+		"error":   "rejected: returned error",
+		"panic":   "rejected: panicked",
+	}
+	for arg, expected := range tests {
+		done := make(chan string)
+		cb := js.FuncOf(func(_ js.Value, args []js.Value) any {
+			go func() { done <- args[0].String() }()
+			return nil
+		})
+		js.Global().Get("Function").New("f", "arg", "cb",
+			`f(arg).then((r) => cb(r), (e) => cb("rejected: " + e.message))`).
+			Invoke(f.Value, arg, cb)
+
+		if received := <-done; received != expected {
+			t.Errorf("Unexpected result for %q.\nexpected: %s\nreceived: %s",
+				arg, expected, received)
+		}
+		cb.Release()
+	}
+}
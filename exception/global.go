@@ -0,0 +1,97 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the events dispatched on globalThis for uncaught Javascript errors.
+const (
+	// ErrorEvent is dispatched when an exception is not caught.
+	ErrorEvent = "error"
+
+	// UnhandledRejectionEvent is dispatched when a Promise is rejected and
+	// has no rejection handler.
+	UnhandledRejectionEvent = "unhandledrejection"
+)
+
+// OnError registers fn to be called with every uncaught Javascript exception,
+// as reported by the error event on globalThis. It works in both window and
+// worker scopes. The thrown value is converted to a Go error in the same way
+// as Catch.
+//
+// fn is called in a new goroutine so that it may block. Call the returned
+// function to unregister fn. Returns an error if globalThis does not support
+// events or if the listener cannot be created with FuncOf.
+func OnError(fn func(err error)) (unregister func(), err error) {
+	return addGlobalListener(ErrorEvent, fn, func(event js.Value) error {
+		if e := event.Get("error"); !e.IsUndefined() && !e.IsNull() {
+			return jsToGoError(e, nil, 0)
+		}
+
+		// Errors from other origins and some syntax errors only have a message
+		// and location
+		e := &JSError{Message: stringProperty(event, "message"), Value: event}
+		if file := stringProperty(event, "filename"); file != "" {
+			e.Stack = []StackFrame{{
+				File:   file,
+				Line:   intProperty(event, "lineno"),
+				Column: intProperty(event, "colno"),
+			}}
+		}
+		return e
+	})
+}
+
+// OnUnhandledRejection registers fn to be called with the reason of every
+// Promise rejection that has no handler, as reported by the unhandledrejection
+// event on globalThis. It works in both window and worker scopes. The reason
+// is converted to a Go error in the same way as Catch.
+//
+// fn is called in a new goroutine so that it may block. Call the returned
+// function to unregister fn. Returns an error if globalThis does not support
+// events or if the listener cannot be created with FuncOf.
+func OnUnhandledRejection(fn func(err error)) (unregister func(), err error) {
+	return addGlobalListener(UnhandledRejectionEvent, fn,
+		func(event js.Value) error {
+			return jsToGoError(event.Get("reason"), nil, 0)
+		})
+}
+
+// addGlobalListener adds an event listener to globalThis that converts each
+// event to an error and passes it to fn. The listener is created with FuncOf so
+// that a panic while converting the event does not crash the Go runtime.
+// Returns a function that removes the listener.
+func addGlobalListener(eventType string, fn func(err error),
+	convert func(event js.Value) error) (func(), error) {
+	global := js.Global()
+	if global.Get("addEventListener").Type() != js.TypeFunction {
+		return nil, errors.Errorf(
+			"cannot listen for %s events: globalThis is not an EventTarget",
+			eventType)
+	} else if _, err := getFuncWrapper(); err != nil {
+		return nil, err
+	}
+
+	listener := FuncOf(func(_ js.Value, args []js.Value) any {
+		err := convert(args[0])
+		Go(func() { fn(err) })
+		return nil
+	})
+	global.Call("addEventListener", eventType, listener.Value)
+
+	return func() {
+		global.Call("removeEventListener", eventType, listener.Value)
+		listener.Release()
+	}, nil
+}
@@ -0,0 +1,94 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// dispatchEvent dispatches an event of the given type with the properties on
+// globalThis. If globalThis is not an EventTarget (e.g., in Node.js), it is
+// made into one.
+var dispatchEvent = js.Global().Get("Function").New("type", "properties",
+	`if (typeof globalThis.addEventListener !== "function") {
+		const target = new EventTarget()
+		for (const m of ["addEventListener", "removeEventListener", "dispatchEvent"]) {
+			globalThis[m] = target[m].bind(target)
+		}
+	}
+	globalThis.dispatchEvent(Object.assign(new Event(type), properties))`)
+
+// Tests that OnError converts error events, both with and without an error, to
+// Go errors and that no more are received once unregistered.
+func TestOnError(t *testing.T) {
+	dispatchEvent.Invoke("init", map[string]any{})
+	received := make(chan error, 1)
+	unregister, err := OnError(func(err error) { received <- err })
+	if err != nil {
+		t.Fatalf("Failed to register handler: %+v", err)
+	}
+
+	thrown := js.Global().Get("TypeError").New("uncaught")
+	dispatchEvent.Invoke(ErrorEvent, map[string]any{"error": thrown})
+	var jsErr *JSError
+	if err = <-received; !errors.As(err, &jsErr) || !jsErr.Value.Equal(thrown) {
+		t.Errorf("Unexpected error.\nexpected: %v\nreceived: %v", thrown, err)
+	} else if jsErr.Name != "TypeError" {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			"TypeError", jsErr.Name)
+	}
+
+	dispatchEvent.Invoke(ErrorEvent, map[string]any{"message": "Script error.",
+		"filename": "https://example.com/a.js", "lineno": 3, "colno": 14})
+	expected := StackFrame{File: "https://example.com/a.js", Line: 3, Column: 14}
+	if err = <-received; !errors.As(err, &jsErr) ||
+		jsErr.Message != "Script error." || len(jsErr.Stack) != 1 ||
+		jsErr.Stack[0] != expected {
+		t.Errorf("Unexpected error for event without error: %+v", err)
+	}
+
+	// Location properties that are not numbers are ignored
+	dispatchEvent.Invoke(ErrorEvent, map[string]any{"message": "Script error.",
+		"filename": "https://example.com/a.js", "lineno": "3", "colno": nil})
+	expected = StackFrame{File: "https://example.com/a.js"}
+	if err = <-received; !errors.As(err, &jsErr) || len(jsErr.Stack) != 1 ||
+		jsErr.Stack[0] != expected {
+		t.Errorf("Unexpected error for event with invalid location: %+v", err)
+	}
+
+	unregister()
+	dispatchEvent.Invoke(ErrorEvent, map[string]any{"error": thrown})
+	select {
+	case err = <-received:
+		t.Errorf("Received error after unregistering: %v", err)
+	default:
+	}
+}
+
+// Tests that OnUnhandledRejection converts the rejection reason to a Go error.
+func TestOnUnhandledRejection(t *testing.T) {
+	dispatchEvent.Invoke("init", map[string]any{})
+	received := make(chan error, 1)
+	unregister, err := OnUnhandledRejection(func(err error) { received <- err })
+	if err != nil {
+		t.Fatalf("Failed to register handler: %+v", err)
+	}
+	defer unregister()
+
+	dispatchEvent.Invoke(UnhandledRejectionEvent,
+		map[string]any{"reason": "rejected"})
+	if err = <-received; err.Error() != "JavaScript error: rejected" {
+		t.Errorf("Unexpected error.\nexpected: %s\nreceived: %v",
+			"JavaScript error: rejected", err)
+	}
+}
@@ -0,0 +1,61 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import "sync"
+
+// Go runs fn in a new goroutine. If fn panics, the panic is recovered,
+// converted to an error with the same rules as Catch, and reported to the
+// Reporter registered with SetReporter instead of terminating the runtime.
+func Go(fn func()) {
+	go func() {
+		defer CatchHandler(Report)
+		fn()
+	}()
+}
+
+// Group is a collection of goroutines working on subtasks of the same task.
+// Panics in the goroutines are recovered and reported like in Go and are
+// returned as errors by Wait.
+//
+// A zero Group is valid and must not be copied after first use.
+type Group struct {
+	wg   sync.WaitGroup
+	once sync.Once
+	err  error
+}
+
+// Go runs fn in a new goroutine. The first non-nil error returned by fn, or
+// recovered from a panic in fn, is returned by Wait.
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer CatchHandler(func(err error) {
+			Report(err)
+			g.setErr(err)
+		})
+		if err := fn(); err != nil {
+			g.setErr(err)
+		}
+	}()
+}
+
+// Wait blocks until all goroutines started with Go have returned and then
+// returns the first error, if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+// setErr saves the error if it is the first error of the group.
+func (g *Group) setErr(err error) {
+	g.once.Do(func() { g.err = err })
+}
@@ -0,0 +1,115 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Tests that a panic in a goroutine started with Go is reported instead of
+// terminating the runtime.
+func TestGo(t *testing.T) {
+	reported := make(chan error)
+	SetReporter(func(err error) { reported <- err })
+	defer SetReporter(nil)
+
+	Go(func() { panic("goroutine panic") })
+
+	if err := <-reported; err.Error() != "goroutine panic" {
+		t.Errorf("Unexpected reported error.\nexpected: %s\nreceived: %v",
+			"goroutine panic", err)
+	}
+}
+
+// Tests that Group.Wait returns the error of the goroutine that panicked and
+// that the panic is reported.
+func TestGroup(t *testing.T) {
+	var reported []error
+	SetReporter(func(err error) { reported = append(reported, err) })
+	defer SetReporter(nil)
+
+	jsErr := js.Global().Get("Error").New("Javascript panic")
+
+	var g Group
+	g.Go(func() error { return nil })
+	g.Go(func() error { panic(jsErr) })
+	err := g.Wait()
+
+	var e *JSError
+	if !errors.As(err, &e) || !e.Value.Equal(jsErr) {
+		t.Errorf("Unexpected error.\nexpected: %v\nreceived: %v", jsErr, err)
+	}
+	if len(reported) != 1 || reported[0] != err {
+		t.Errorf("Panic not reported.\nexpected: %v\nreceived: %v",
+			[]error{err}, reported)
+	}
+}
+
+// Tests that Group.Wait returns the error returned by a goroutine.
+func TestGroup_Error(t *testing.T) {
+	expected := errors.New("returned error")
+
+	var g Group
+	g.Go(func() error { return expected })
+	if err := g.Wait(); err != expected {
+		t.Errorf("Unexpected error.\nexpected: %v\nreceived: %v", expected, err)
+	}
+}
+
+// Tests that MultiReporter passes the error to each reporter and that
+// JSReporter calls the Javascript callback with an Error.
+func TestMultiReporter(t *testing.T) {
+	var goReported error
+	var jsReported js.Value
+	cb := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		jsReported = args[0]
+		return nil
+	})
+	defer cb.Release()
+
+	expected := errors.New("reported error")
+	MultiReporter(func(err error) { goReported = err }, nil,
+		JSReporter(cb.Value))(expected)
+
+	if goReported != expected {
+		t.Errorf("Go reporter not called.\nexpected: %v\nreceived: %v",
+			expected, goReported)
+	}
+	if !jsReported.InstanceOf(Error) {
+		t.Errorf("Javascript reporter not called with an Error: %v", jsReported)
+	}
+}
+
+// Tests that a Reporter that panics and a JSReporter whose callback throws do
+// not panic and do not prevent the other reporters from being called.
+func TestMultiReporter_Panic(t *testing.T) {
+	throwing := js.Global().Get("Function").New(`throw Error("callback error")`)
+
+	var reported error
+	expected := errors.New("reported error")
+	SetReporter(MultiReporter(
+		func(error) { panic("reporter panic") },
+		JSReporter(throwing),
+		func(err error) { reported = err },
+	))
+	defer SetReporter(nil)
+
+	Report(expected)
+	if reported != expected {
+		t.Errorf("Reporter not called after others failed."+
+			"\nexpected: %v\nreceived: %v", expected, reported)
+	}
+
+	SetReporter(func(error) { panic("reporter panic") })
+	Report(expected)
+}
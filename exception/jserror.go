@@ -0,0 +1,272 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+)
+
+// JSError is a Go error containing a Javascript exception. It is returned by
+// Catch, CatchHandler, and RunAndCatch when Javascript throws a value.
+//
+// JSError matches *js.Error when using errors.As so that it can be used in
+// place of the js.Error returned by syscall/js.
+type JSError struct {
+	// Name is the name of the Javascript Error class (e.g., "TypeError").
+	// Empty if the thrown value is not an object.
+	Name string
+
+	// Message is the Javascript error message. If the thrown value is not an
+	// object, it is the value converted to a string.
+	Message string
+
+	// Stack is the Javascript stack trace parsed into frames.
+	Stack []StackFrame
+
+	// RawStack is the unparsed Javascript stack trace.
+	RawStack string
+
+	// Code is the code property of the Javascript Error, if it has one.
+	Code string
+
+	// Cause is the cause of the Javascript Error converted to a Go error. Nil
+	// if the Error has no cause.
+	Cause error
+
+	// Value is the raw value thrown by Javascript.
+	Value js.Value
+
+	// recoveredAt is the Go stack trace of the site where the exception
+	// entered Go, such as a call to js.Value.Call.
+	recoveredAt errors.StackTrace
+}
+
+// JSAggregateError is a Go error containing a Javascript AggregateError. Each
+// of its errors is converted to a Go error and returned by Unwrap, with the
+// AggregateError's cause, if it has one, as the last error.
+//
+// JSAggregateError matches *JSError and *js.Error when using errors.As.
+type JSAggregateError struct {
+	*JSError
+
+	// Errors are the errors of the AggregateError converted to Go errors.
+	Errors []error
+}
+
+// StackFrame is a single frame of a Javascript stack trace.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+	Column   int
+}
+
+// String returns the frame in the format "function (file:line:column)".
+func (sf StackFrame) String() string {
+	location := sf.File
+	if sf.Line > 0 {
+		location += ":" + strconv.Itoa(sf.Line)
+		if sf.Column > 0 {
+			location += ":" + strconv.Itoa(sf.Column)
+		}
+	}
+	if sf.Function == "" {
+		return location
+	}
+	return sf.Function + " (" + location + ")"
+}
+
+// Error returns the Javascript error message in the same format as js.Error.
+func (e *JSError) Error() string { return "JavaScript error: " + e.Message }
+
+// Unwrap returns the cause of the Javascript Error.
+func (e *JSError) Unwrap() error { return e.Cause }
+
+// As sets target to a js.Error containing the thrown value if target is a
+// *js.Error.
+func (e *JSError) As(target any) bool {
+	if t, ok := target.(*js.Error); ok {
+		*t = js.Error{Value: e.Value}
+		return true
+	}
+	return false
+}
+
+// RecoveredAt returns the Go stack trace of the site where the Javascript
+// exception entered Go, such as the call to js.Value.Call that threw it.
+func (e *JSError) RecoveredAt() errors.StackTrace { return e.recoveredAt }
+
+// Format implements fmt.Formatter. The verbs %s and %v print the error message
+// and %q prints it quoted. The flag %+v additionally prints the Javascript stack
+// trace, the Go stack trace of the recovery site, and the cause.
+func (e *JSError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.formatVerbose(s)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// formatVerbose writes the error with its Javascript and Go stack traces and
+// its cause.
+func (e *JSError) formatVerbose(w io.Writer) {
+	_, _ = io.WriteString(w, e.Error())
+	if e.Name != "" {
+		_, _ = fmt.Fprintf(w, "\n%s", e.Name)
+	}
+	if len(e.Stack) > 0 {
+		_, _ = io.WriteString(w, "\nJavascript stack:")
+		for _, frame := range e.Stack {
+			_, _ = fmt.Fprintf(w, "\n\tat %s", frame)
+		}
+	}
+	if len(e.recoveredAt) > 0 {
+		_, _ = fmt.Fprintf(w, "\nGo recovery site:%+v", e.recoveredAt)
+	}
+	if e.Cause != nil {
+		_, _ = fmt.Fprintf(w, "\nCaused by: %+v", e.Cause)
+	}
+}
+
+// Unwrap returns the converted errors of the Javascript AggregateError.
+func (e *JSAggregateError) Unwrap() []error { return e.Errors }
+
+// As sets target to the JSError if target is a **JSError or to a js.Error
+// containing the thrown value if target is a *js.Error.
+func (e *JSAggregateError) As(target any) bool {
+	if t, ok := target.(**JSError); ok {
+		*t = e.JSError
+		return true
+	}
+	return e.JSError.As(target)
+}
+
+// Format implements fmt.Formatter. It prints the same as JSError.Format, with
+// each of the aggregated errors when using %+v.
+func (e *JSAggregateError) Format(s fmt.State, verb rune) {
+	e.JSError.Format(s, verb)
+	if verb == 'v' && s.Flag('+') {
+		for i, err := range e.Errors {
+			_, _ = fmt.Fprintf(s, "\nError %d: %+v", i, err)
+		}
+	}
+}
+
+// NewJSError converts the thrown Javascript value to a Go error in the same way
+// as Catch, for exceptions that were caught in Javascript and returned to Go.
+func NewJSError(v js.Value) error {
+	return jsToGoError(v, nil, 0)
+}
+
+// jsToGoError converts the Javascript value to a Go error. The cause chain of a
+// Javascript Error is converted so that it can be inspected with errors.Unwrap,
+// errors.Is, and errors.As, and the errors of an AggregateError are converted
+// to a Go error that wraps multiple errors.
+func jsToGoError(v js.Value, recoveredAt errors.StackTrace, depth int) error {
+	e := &JSError{Value: v, recoveredAt: recoveredAt}
+	if v.Type() != js.TypeObject {
+		e.Message = js.Global().Get("String").Invoke(v).String()
+		return e
+	}
+
+	e.Name = stringProperty(v, "name")
+	e.Message = stringProperty(v, "message")
+	e.RawStack = stringProperty(v, "stack")
+	e.Stack = parseStack(e.RawStack)
+	e.Code = stringProperty(v, "code")
+
+	if c := v.Get("cause"); !c.IsUndefined() && depth < maxCauseDepth {
+		e.Cause = jsToGoError(c, nil, depth+1)
+	}
+
+	if c, _ := GetClass(AggregateErrorClass); c.Truthy() && v.InstanceOf(c) {
+		jsErrs := v.Get("errors")
+		errs := make([]error, 0, jsErrs.Length()+1)
+		for i := 0; i < jsErrs.Length() && depth < maxCauseDepth; i++ {
+			errs = append(errs, jsToGoError(jsErrs.Index(i), nil, depth+1))
+		}
+		if e.Cause != nil {
+			errs = append(errs, e.Cause)
+		}
+		return &JSAggregateError{e, errs}
+	}
+
+	return e
+}
+
+// stringProperty returns the property of the Javascript object as a string.
+// Returns an empty string if the property is undefined or null.
+func stringProperty(v js.Value, property string) string {
+	p := v.Get(property)
+	switch p.Type() {
+	case js.TypeUndefined, js.TypeNull:
+		return ""
+	case js.TypeString:
+		return p.String()
+	default:
+		return js.Global().Get("String").Invoke(p).String()
+	}
+}
+
+// intProperty returns the property of the Javascript object as an int. Returns
+// 0 if the property is not a number.
+func intProperty(v js.Value, property string) int {
+	if p := v.Get(property); p.Type() == js.TypeNumber {
+		return p.Int()
+	}
+	return 0
+}
+
+var (
+	// v8FrameRegex matches a V8 (Chrome, Node) stack frame in the form
+	// "at function (file:line:column)" or "at file:line:column".
+	v8FrameRegex = regexp.MustCompile(
+		`^\s*at (?:(.+?) \()?(.+?)(?::(\d+))?(?::(\d+))?\)?$`)
+
+	// geckoFrameRegex matches a SpiderMonkey (Firefox) or JavaScriptCore
+	// (Safari) stack frame in the form "function@file:line:column".
+	geckoFrameRegex = regexp.MustCompile(`^(.*?)@(.+?)(?::(\d+))?(?::(\d+))?$`)
+)
+
+// parseStack parses a Javascript stack trace into frames. Lines that are not
+// recognized as stack frames, such as the error message in V8 stack traces,
+// are skipped.
+func parseStack(stack string) []StackFrame {
+	var frames []StackFrame
+	for _, line := range strings.Split(stack, "\n") {
+		m := v8FrameRegex.FindStringSubmatch(line)
+		if m == nil {
+			m = geckoFrameRegex.FindStringSubmatch(strings.TrimSpace(line))
+		}
+		if m == nil {
+			continue
+		}
+
+		frame := StackFrame{Function: m[1], File: m[2]}
+		frame.Line, _ = strconv.Atoi(m[3])
+		frame.Column, _ = strconv.Atoi(m[4])
+		frames = append(frames, frame)
+	}
+	return frames
+}
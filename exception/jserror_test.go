@@ -0,0 +1,147 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+// Tests that Catch returns a JSError with all the fields of the thrown
+// Javascript Error.
+func TestCatch_JSError(t *testing.T) {
+	resultErr := func() (err error) {
+		defer Catch(&err)
+		js.Global().Get("Function").New(`const e = new TypeError("bad type", ` +
+			`{cause: new Error("inner")}); e.code = "E_BAD"; throw e`).Invoke()
+		return nil
+	}()
+
+	var jsErr *JSError
+	if !errors.As(resultErr, &jsErr) {
+		t.Fatalf("Error is not a JSError: %T", resultErr)
+	}
+
+	if jsErr.Name != TypeErrorClass {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			TypeErrorClass, jsErr.Name)
+	}
+	if jsErr.Message != "bad type" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"bad type", jsErr.Message)
+	}
+	if jsErr.Code != "E_BAD" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s",
+			"E_BAD", jsErr.Code)
+	}
+	if len(jsErr.Stack) == 0 {
+		t.Errorf("Stack not parsed: %q", jsErr.RawStack)
+	}
+	if jsErr.Value.Get("message").String() != "bad type" {
+		t.Errorf("Incorrect raw value: %s", JsErrorToJson(jsErr.Value))
+	}
+	if len(jsErr.RecoveredAt()) == 0 {
+		t.Errorf("Go recovery site not recorded.")
+	}
+
+	var cause *JSError
+	if !errors.As(jsErr.Cause, &cause) || cause.Message != "inner" {
+		t.Errorf("Incorrect cause: %+v", jsErr.Cause)
+	}
+
+	verbose := fmt.Sprintf("%+v", resultErr)
+	for _, s := range []string{
+		"bad type", "Javascript stack:", "Go recovery site:", "inner"} {
+		if !strings.Contains(verbose, s) {
+			t.Errorf("%%+v output does not contain %q:\n%s", s, verbose)
+		}
+	}
+
+	if s := fmt.Sprintf("%v", resultErr); s != "JavaScript error: bad type" {
+		t.Errorf("Incorrect %%v output.\nexpected: %s\nreceived: %s",
+			"JavaScript error: bad type", s)
+	}
+}
+
+// Tests that Catch returns a JSError containing the string value of a thrown
+// value that is not an object.
+func TestCatch_JSErrorNonObject(t *testing.T) {
+	resultErr := func() (err error) {
+		defer Catch(&err)
+		js.Global().Get("Function").New(`throw 42`).Invoke()
+		return nil
+	}()
+
+	var jsErr *JSError
+	if !errors.As(resultErr, &jsErr) {
+		t.Fatalf("Error is not a JSError: %T", resultErr)
+	} else if jsErr.Message != "42" || jsErr.Name != "" {
+		t.Errorf("Unexpected JSError for non-object: %+v", jsErr)
+	}
+}
+
+// Tests that NewJSError converts a Javascript Error and its cause to a
+// *JSError containing the value.
+func TestNewJSError(t *testing.T) {
+	cause := js.Global().Get(RangeErrorClass).New("root cause")
+	v := js.Global().Get(TypeErrorClass).New(
+		"bad", map[string]any{"cause": cause})
+
+	var jsErr *JSError
+	if err := NewJSError(v); !errors.As(err, &jsErr) {
+		t.Fatalf("Error is not a JSError: %+v", err)
+	}
+	if jsErr.Name != TypeErrorClass || jsErr.Message != "bad" ||
+		!jsErr.Value.Equal(v) {
+		t.Errorf("Incorrect JSError.\nexpected: %s: %s\nreceived: %s: %s",
+			TypeErrorClass, "bad", jsErr.Name, jsErr.Message)
+	}
+	var causeErr *JSError
+	if !errors.As(jsErr.Cause, &causeErr) || causeErr.Message != "root cause" {
+		t.Errorf("Incorrect cause: %+v", jsErr.Cause)
+	}
+}
+
+// Tests that parseStack parses V8 and SpiderMonkey stack traces.
+func Test_parseStack(t *testing.T) {
+	tests := []struct {
+		stack    string
+		expected []StackFrame
+	}{{
+		stack: "TypeError: bad\n" +
+			"    at foo (http://localhost:8080/main.js:10:5)\n" +
+			"    at http://localhost:8080/main.js:20:15\n" +
+			"    at new Bar (file.js:3:1)",
+		expected: []StackFrame{
+			{"foo", "http://localhost:8080/main.js", 10, 5},
+			{"", "http://localhost:8080/main.js", 20, 15},
+			{"new Bar", "file.js", 3, 1},
+		},
+	}, {
+		stack: "foo@http://localhost:8080/main.js:10:5\n" +
+			"@http://localhost:8080/main.js:20:15\n",
+		expected: []StackFrame{
+			{"foo", "http://localhost:8080/main.js", 10, 5},
+			{"", "http://localhost:8080/main.js", 20, 15},
+		},
+	}}
+
+	for i, tt := range tests {
+		frames := parseStack(tt.stack)
+		if !reflect.DeepEqual(tt.expected, frames) {
+			t.Errorf("Unexpected frames (%d).\nexpected: %+v\nreceived: %+v",
+				i, tt.expected, frames)
+		}
+	}
+}
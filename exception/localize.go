@@ -0,0 +1,211 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall/js"
+)
+
+// FallbackLocale is the locale used when the catalog has no template for the
+// current locale.
+const FallbackLocale = "en"
+
+// localizedMessageProperty is the property of the Javascript Error that
+// contains the localized message.
+const localizedMessageProperty = "localizedMessage"
+
+// Catalog provides the message templates used to localize errors by their
+// code.
+//
+// Templates contain parameters in the form {name}, which are replaced with the
+// value of the parameter with that name. Parameters without a value are left
+// as is.
+type Catalog interface {
+	// Template returns the message template for the error code in the locale.
+	// Returns false if the catalog has no template for the code in the locale.
+	Template(locale, code string) (template string, exists bool)
+}
+
+// MapCatalog is a Catalog that maps each locale to a map of error codes to
+// message templates.
+//
+// Example:
+//
+//	exception.MapCatalog{
+//		"en": {"E_QUOTA": "Storage is full ({used} of {max} bytes used)."},
+//		"fr": {"E_QUOTA": "Le stockage est plein ({used} sur {max} octets)."},
+//	}
+type MapCatalog map[string]map[string]string
+
+// Template returns the message template for the error code in the locale.
+func (mc MapCatalog) Template(locale, code string) (string, bool) {
+	template, exists := mc[locale][code]
+	return template, exists
+}
+
+// LocalizableError is implemented by errors that have a code and parameters
+// used to localize their message. Errors can be given a code with WithCode.
+type LocalizableError interface {
+	error
+
+	// ErrorCode returns the code of the error.
+	ErrorCode() string
+
+	// ErrorParams returns the parameters used to render the localized message.
+	ErrorParams() map[string]any
+}
+
+// localization contains the catalog and locale used to localize errors.
+var localization = struct {
+	catalog Catalog
+	locale  string
+	mux     sync.RWMutex
+}{}
+
+// SetCatalog sets the catalog used to localize errors. When an error with a
+// code is converted to a Javascript Error, it has a localizedMessage property
+// containing the message rendered from the catalog in the current locale. Set
+// to nil to disable localization.
+func SetCatalog(c Catalog) {
+	localization.mux.Lock()
+	defer localization.mux.Unlock()
+	localization.catalog = c
+}
+
+// SetLocale sets the locale used to localize errors (e.g., "fr-CA"). If empty,
+// the locale of the browser is used.
+func SetLocale(locale string) {
+	localization.mux.Lock()
+	defer localization.mux.Unlock()
+	localization.locale = locale
+}
+
+// Locale returns the locale used to localize errors. It is the locale set with
+// SetLocale or, if none is set, the locale of the browser (navigator.language).
+// Returns FallbackLocale if neither is available.
+func Locale() string {
+	localization.mux.RLock()
+	locale := localization.locale
+	localization.mux.RUnlock()
+	if locale != "" {
+		return locale
+	}
+
+	navigator := js.Global().Get("navigator")
+	if navigator.Type() == js.TypeObject {
+		if lang := navigator.Get("language"); lang.Type() == js.TypeString {
+			return lang.String()
+		}
+	}
+	return FallbackLocale
+}
+
+// Localize renders the message for the error code with the parameters in the
+// current locale. Returns false if no catalog is set or it has no template for
+// the code.
+func Localize(code string, params map[string]any) (string, bool) {
+	return LocalizeIn(Locale(), code, params)
+}
+
+// LocalizeIn renders the message for the error code with the parameters in the
+// given locale. If the catalog has no template for the locale, the base
+// language of the locale (e.g., "fr" for "fr-CA") and then FallbackLocale are
+// tried. Returns false if no catalog is set or it has no template for the code.
+func LocalizeIn(locale, code string, params map[string]any) (string, bool) {
+	localization.mux.RLock()
+	catalog := localization.catalog
+	localization.mux.RUnlock()
+	if catalog == nil {
+		return "", false
+	}
+
+	for _, l := range localeFallbacks(locale) {
+		if template, exists := catalog.Template(l, code); exists {
+			return renderTemplate(template, params), true
+		}
+	}
+	return "", false
+}
+
+// localeFallbacks returns the locales to try, in order, for the locale.
+func localeFallbacks(locale string) []string {
+	locales := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locales = append(locales, locale[:i])
+	}
+	if locales[len(locales)-1] != FallbackLocale {
+		locales = append(locales, FallbackLocale)
+	}
+	return locales
+}
+
+// templateParamRegex matches a parameter in a message template.
+var templateParamRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// renderTemplate replaces each parameter in the template with its value.
+func renderTemplate(template string, params map[string]any) string {
+	return templateParamRegex.ReplaceAllStringFunc(template, func(p string) string {
+		if v, exists := params[p[1:len(p)-1]]; exists {
+			return fmt.Sprint(v)
+		}
+		return p
+	})
+}
+
+// localizableError is an error with a code and parameters.
+type localizableError struct {
+	err    error
+	code   string
+	params map[string]any
+}
+
+// WithCode annotates the error with a code and the parameters used to localize
+// its message. When converted to a Javascript Error, the Error has the code,
+// params, and localizedMessage properties. Returns nil if err is nil.
+//
+// A code set with a Mapping takes precedence over the code set with WithCode.
+func WithCode(err error, code string, params map[string]any) error {
+	if err == nil {
+		return nil
+	}
+	return &localizableError{err, code, params}
+}
+
+// Error returns the message of the annotated error.
+func (e *localizableError) Error() string { return e.err.Error() }
+
+// Unwrap returns the annotated error.
+func (e *localizableError) Unwrap() error { return e.err }
+
+// ErrorCode returns the code of the error.
+func (e *localizableError) ErrorCode() string { return e.code }
+
+// ErrorParams returns the parameters used to render the localized message.
+func (e *localizableError) ErrorParams() map[string]any { return e.params }
+
+// Format implements fmt.Formatter by formatting the annotated error.
+func (e *localizableError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = fmt.Fprintf(s, "%+v", e.err)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = fmt.Fprint(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
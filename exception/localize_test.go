@@ -0,0 +1,97 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// testCatalog is the catalog used in the localization tests.
+var testCatalog = MapCatalog{
+	"en": {
+		"E_QUOTA":   "Storage is full ({used} of {max} bytes used).",
+		"E_ENGLISH": "Only in English.",
+	},
+	"fr": {"E_QUOTA": "Le stockage est plein ({used} sur {max} octets)."},
+}
+
+// Tests that LocalizeIn renders the template of the locale, falling back to
+// the base language and then to FallbackLocale.
+func TestLocalizeIn(t *testing.T) {
+	SetCatalog(testCatalog)
+	defer SetCatalog(nil)
+	params := map[string]any{"used": 10, "max": 10}
+
+	tests := []struct{ locale, code, expected string }{
+		{"en", "E_QUOTA", "Storage is full (10 of 10 bytes used)."},
+		{"fr-CA", "E_QUOTA", "Le stockage est plein (10 sur 10 octets)."},
+		{"fr", "E_ENGLISH", "Only in English."},
+	}
+	for _, tt := range tests {
+		message, ok := LocalizeIn(tt.locale, tt.code, params)
+		if !ok || message != tt.expected {
+			t.Errorf("Unexpected message for %s in %s."+
+				"\nexpected: %s\nreceived: %s", tt.code, tt.locale,
+				tt.expected, message)
+		}
+	}
+
+	if _, ok := LocalizeIn("en", "E_UNKNOWN", nil); ok {
+		t.Errorf("Localized unknown code.")
+	}
+}
+
+// Tests that renderTemplate leaves parameters without a value as is.
+func Test_renderTemplate(t *testing.T) {
+	s := renderTemplate("{a} and {b}", map[string]any{"a": "x"})
+	if s != "x and {b}" {
+		t.Errorf("Unexpected rendered template.\nexpected: %s\nreceived: %s",
+			"x and {b}", s)
+	}
+}
+
+// Tests that NewError sets the code, params, and localized message of an
+// error annotated with WithCode and of an error with a Mapping.
+func TestNewError_Localized(t *testing.T) {
+	SetCatalog(testCatalog)
+	defer SetCatalog(nil)
+	SetLocale("fr")
+	defer SetLocale("")
+
+	err := WithCode(errors.New("quota exceeded"), "E_QUOTA",
+		map[string]any{"used": 5, "max": 5})
+	jsErr := NewError(err)
+	expected := "Le stockage est plein (5 sur 5 octets)."
+	if m := jsErr.Get("localizedMessage").String(); m != expected {
+		t.Errorf("Incorrect localized message.\nexpected: %s\nreceived: %s",
+			expected, m)
+	}
+	if m := jsErr.Get("message").String(); m != "quota exceeded" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"quota exceeded", m)
+	}
+	if c := jsErr.Get("code").String(); c != "E_QUOTA" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s", "E_QUOTA", c)
+	}
+	if p := jsErr.Get("params").Get("max").Int(); p != 5 {
+		t.Errorf("Incorrect params.\nexpected: %d\nreceived: %d", 5, p)
+	}
+
+	sentinel := errors.New("only english")
+	RegisterError(sentinel, Mapping{Code: "E_ENGLISH",
+		Params: func(error) map[string]any { return map[string]any{} }})
+	jsErr = NewError(sentinel)
+	if m := jsErr.Get("localizedMessage").String(); m != "Only in English." {
+		t.Errorf("Incorrect localized message.\nexpected: %s\nreceived: %s",
+			"Only in English.", m)
+	}
+}
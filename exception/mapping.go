@@ -0,0 +1,215 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"encoding/json"
+	"sync"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Mapping describes how a Go error is represented as a Javascript Error.
+type Mapping struct {
+	// Class is the name of the Javascript Error class used for the error. It
+	// can be a built-in class, such as TypeErrorClass, or a custom class
+	// registered with RegisterClass. If empty, ErrorClass is used.
+	Class string
+
+	// Code is a stable identifier for the error that is set as the code
+	// property on the Javascript Error, so that Javascript can handle errors
+	// without matching on their message.
+	Code string
+
+	// Details, if set, returns structured information about the error that is
+	// set as the details property on the Javascript Error. It is called with
+	// the matched error and its return value must be JSON serializable.
+	Details func(err error) map[string]any
+
+	// Params, if set, returns the parameters used to render the localized
+	// message of the error from the catalog set with SetCatalog. They are set
+	// as the params property on the Javascript Error. It is called with the
+	// matched error and its return value must be JSON serializable.
+	Params func(err error) map[string]any
+}
+
+// mapping is a registered Mapping and the function used to match errors to it.
+type mapping struct {
+	Mapping
+
+	// match returns the matched error and true if err matches the mapping.
+	match func(err error) (error, bool)
+}
+
+// mappings contains all registered mappings in the order they were registered.
+var mappings = struct {
+	list []mapping
+	mux  sync.RWMutex
+}{}
+
+// RegisterError registers the mapping for the sentinel error. Any error that
+// matches target, as reported by errors.Is, is converted using the mapping by
+// Throw, NewError, and the other functions that create Javascript errors.
+//
+// Mappings are checked in the order they are registered and the first match is
+// used.
+func RegisterError(target error, m Mapping) {
+	addMapping(m, func(err error) (error, bool) {
+		return err, errors.Is(err, target)
+	})
+}
+
+// RegisterErrorType registers the mapping for the error type T. Any error that
+// matches T, as reported by errors.As, is converted using the mapping by Throw,
+// NewError, and the other functions that create Javascript errors. The matched
+// error of type T is passed to Mapping.Details.
+//
+// Mappings are checked in the order they are registered and the first match is
+// used.
+func RegisterErrorType[T error](m Mapping) {
+	addMapping(m, func(err error) (error, bool) {
+		var target T
+		if errors.As(err, &target) {
+			return target, true
+		}
+		return nil, false
+	})
+}
+
+// addMapping adds the mapping to the list of registered mappings.
+func addMapping(m Mapping, match func(err error) (error, bool)) {
+	mappings.mux.Lock()
+	defer mappings.mux.Unlock()
+	mappings.list = append(mappings.list, mapping{m, match})
+}
+
+// lookupMapping returns the first registered mapping that matches the error
+// and the matched error. Returns false if no mapping matches.
+func lookupMapping(err error) (Mapping, error, bool) {
+	mappings.mux.RLock()
+	defer mappings.mux.RUnlock()
+	for _, m := range mappings.list {
+		if matched, ok := m.match(err); ok {
+			return m.Mapping, matched, true
+		}
+	}
+	return Mapping{}, nil, false
+}
+
+// errorProperties returns the Javascript Error class, and the properties to set
+// on the Error, for the given Go error based on its registered mapping. If no
+// mapping exists, ErrorClass is returned.
+//
+// The code and parameters come from the mapping or, if the mapping has no
+// code, from a LocalizableError in the chain. If the error has a code, its
+// parameters and localized message are added to the properties. The string
+// values of the details and parameters and the localized message are
+// sanitized with Sanitize, like the error message.
+func errorProperties(err error) (class string, properties map[string]any) {
+	class = ErrorClass
+	properties = make(map[string]any)
+
+	var code string
+	var params map[string]any
+	if m, matched, exists := lookupMapping(err); exists {
+		class, code = classOrError(m.Class), m.Code
+		if m.Details != nil {
+			properties["details"] = sanitizeValues(m.Details(matched))
+		}
+		if m.Params != nil {
+			params = m.Params(matched)
+		}
+	}
+
+	var le LocalizableError
+	if code == "" && errors.As(err, &le) {
+		code, params = le.ErrorCode(), le.ErrorParams()
+	}
+
+	if code != "" {
+		properties["code"] = code
+		if params != nil {
+			properties["params"] = sanitizeValues(params)
+		}
+		if message, ok := Localize(code, params); ok {
+			properties[localizedMessageProperty] = Sanitize(message)
+		}
+	}
+
+	if len(properties) == 0 {
+		return class, nil
+	}
+	return class, properties
+}
+
+// sanitizeValues returns a copy of the values with each string, including those
+// nested in maps and slices, sanitized with Sanitize. Other values are
+// returned as is.
+func sanitizeValues(values map[string]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+	sanitized := make(map[string]any, len(values))
+	for k, v := range values {
+		sanitized[k] = sanitizeValue(v)
+	}
+	return sanitized
+}
+
+// sanitizeValue sanitizes the value if it is a string or sanitizes its
+// elements if it is a map or slice.
+func sanitizeValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		return Sanitize(val)
+	case []string:
+		sanitized := make([]string, len(val))
+		for i, s := range val {
+			sanitized[i] = Sanitize(s)
+		}
+		return sanitized
+	case []any:
+		sanitized := make([]any, len(val))
+		for i, e := range val {
+			sanitized[i] = sanitizeValue(e)
+		}
+		return sanitized
+	case map[string]any:
+		return sanitizeValues(val)
+	}
+	return v
+}
+
+// propertiesJson marshals the properties to a JSON object. An empty object is
+// returned if the properties cannot be marshalled.
+func propertiesJson(properties map[string]any) string {
+	if len(properties) == 0 {
+		return "{}"
+	}
+	data, err := json.Marshal(properties)
+	if err != nil {
+		jww.ERROR.Printf(
+			"Failed to marshal Javascript error properties: %+v", err)
+		return "{}"
+	}
+	return string(data)
+}
+
+// setProperties sets each property on the Javascript Error. Properties are
+// converted via JSON so that any JSON serializable value is supported.
+func setProperties(jsErr js.Value, properties map[string]any) js.Value {
+	if len(properties) > 0 {
+		props := js.Global().Get("JSON").Call("parse", propertiesJson(properties))
+		js.Global().Get("Object").Call("assign", jsErr, props)
+	}
+	return jsErr
+}
@@ -0,0 +1,129 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"github.com/pkg/errors"
+	"syscall/js"
+	"testing"
+)
+
+// testMappingError is an error type used to test RegisterErrorType.
+type testMappingError struct{ field string }
+
+func (e *testMappingError) Error() string { return "test error " + e.field }
+
+// Tests that NewError uses the mapping registered with RegisterError for a
+// wrapped sentinel error.
+func TestRegisterError(t *testing.T) {
+	sentinel := errors.New("sentinel error")
+	RegisterError(sentinel, Mapping{Class: RangeErrorClass, Code: "E_SENTINEL"})
+
+	jsErr := NewError(errors.Wrap(sentinel, "wrapped"))
+	if !jsErr.InstanceOf(js.Global().Get(RangeErrorClass)) {
+		t.Errorf("Error is not a %s: %s",
+			RangeErrorClass, jsErr.Get("name").String())
+	}
+	if code := jsErr.Get("code").String(); code != "E_SENTINEL" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s",
+			"E_SENTINEL", code)
+	}
+	if msg := jsErr.Get("message").String(); msg != "wrapped: sentinel error" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"wrapped: sentinel error", msg)
+	}
+}
+
+// Tests that NewError uses the mapping registered with RegisterErrorType and
+// passes the matched error to Mapping.Details.
+func TestRegisterErrorType(t *testing.T) {
+	RegisterErrorType[*testMappingError](Mapping{
+		Code: "E_TYPE",
+		Details: func(err error) map[string]any {
+			return map[string]any{
+				"field": err.(*testMappingError).field,
+				"list":  []string{"a", "b"},
+			}
+		},
+	})
+
+	jsErr := NewTrace(errors.WithStack(&testMappingError{"value"}))
+	if code := jsErr.Get("code").String(); code != "E_TYPE" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s", "E_TYPE", code)
+	}
+	details := jsErr.Get("details")
+	if field := details.Get("field").String(); field != "value" {
+		t.Errorf("Incorrect details field.\nexpected: %s\nreceived: %s",
+			"value", field)
+	}
+	if n := details.Get("list").Length(); n != 2 {
+		t.Errorf("Incorrect details list length.\nexpected: %d\nreceived: %d",
+			2, n)
+	}
+}
+
+// Tests that NewError creates a plain Error without a code for errors that do
+// not have a mapping.
+func TestNewError_NoMapping(t *testing.T) {
+	jsErr := NewError(errors.New("unmapped error"))
+	if name := jsErr.Get("name").String(); name != ErrorClass {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s", ErrorClass, name)
+	}
+	if !jsErr.Get("code").IsUndefined() {
+		t.Errorf("Unmapped error has code: %s", jsErr.Get("code").String())
+	}
+}
+
+// Tests that NewError sanitizes the string values of the details and params of
+// a mapping and the localized message rendered from them.
+func TestNewError_MappingSanitized(t *testing.T) {
+	SetSanitizePolicy(SanitizePolicy{Redactions: []Redaction{
+		{DefaultRedactions[1].Pattern, "$1$2***"}}})
+	defer SetSanitizePolicy(DefaultSanitizePolicy)
+	SetCatalog(MapCatalog{"en": {"E_SANITIZED": "Failed with {value}"}})
+	defer SetCatalog(nil)
+	SetLocale("en")
+	defer SetLocale("")
+
+	sentinel := errors.New("sanitized mapping error")
+	RegisterError(sentinel, Mapping{
+		Code: "E_SANITIZED",
+		Details: func(error) map[string]any {
+			return map[string]any{
+				"value":  "token=secret",
+				"nested": map[string]any{"list": []any{"token=secret", 5}},
+			}
+		},
+		Params: func(error) map[string]any {
+			return map[string]any{"value": "token=secret"}
+		},
+	})
+
+	jsErr := NewError(sentinel)
+	values := map[string]js.Value{
+		"details.value":  jsErr.Get("details").Get("value"),
+		"details.nested": jsErr.Get("details").Get("nested").Get("list").Index(0),
+		"params.value":   jsErr.Get("params").Get("value"),
+	}
+	for name, v := range values {
+		if v.String() != "token=***" {
+			t.Errorf("%s not sanitized.\nexpected: %s\nreceived: %s",
+				name, "token=***", v.String())
+		}
+	}
+	if n := jsErr.Get("details").Get("nested").Get("list").Index(1).Int(); n != 5 {
+		t.Errorf("Non-string value changed.\nexpected: %d\nreceived: %d", 5, n)
+	}
+	expected := "Failed with token=***"
+	if m := jsErr.Get(localizedMessageProperty).String(); m != expected {
+		t.Errorf("Localized message not sanitized."+
+			"\nexpected: %s\nreceived: %s", expected, m)
+	}
+}
@@ -0,0 +1,92 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"sync"
+	"syscall/js"
+
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Reporter is called with every panic recovered by this package, such as those
+// recovered by FuncOf and Go.
+type Reporter func(err error)
+
+// reporter is the registered Reporter.
+var reporter = struct {
+	fn  Reporter
+	mux sync.RWMutex
+}{}
+
+// SetReporter registers the function that is called with every panic recovered
+// by this package. If nil, recovered panics are logged with jwalterweatherman.
+//
+// The console package provides a Reporter that prints to the browser console
+// and the storage package provides a crash log that persists reports. Use
+// MultiReporter to register more than one.
+func SetReporter(fn Reporter) {
+	reporter.mux.Lock()
+	defer reporter.mux.Unlock()
+	reporter.fn = fn
+}
+
+// Report passes the error to the registered Reporter or logs it if there is
+// none. It is used to report panics recovered outside this package. If the
+// Reporter panics, the panic is recovered and logged along with the error.
+func Report(err error) {
+	reporter.mux.RLock()
+	fn := reporter.fn
+	reporter.mux.RUnlock()
+
+	if fn == nil {
+		jww.ERROR.Printf("Recovered from panic: %+v", err)
+		return
+	}
+	callReporter(fn, err)
+}
+
+// MultiReporter returns a Reporter that passes each error to all the reporters
+// in order. A reporter that panics does not prevent the others from being
+// called.
+func MultiReporter(reporters ...Reporter) Reporter {
+	return func(err error) {
+		for _, r := range reporters {
+			if r != nil {
+				callReporter(r, err)
+			}
+		}
+	}
+}
+
+// JSReporter returns a Reporter that calls the Javascript callback with each
+// error converted to a Javascript Error using NewTrace. If the callback throws,
+// the exception is logged along with the error.
+func JSReporter(callback js.Value) Reporter {
+	return func(err error) {
+		_, callbackErr := RunAndCatch(
+			func() js.Value { return callback.Invoke(NewTrace(err)) })
+		if callbackErr != nil {
+			jww.ERROR.Printf("Javascript reporter threw %+v while reporting: %+v",
+				callbackErr, err)
+		}
+	}
+}
+
+// callReporter calls the Reporter with the error. Reporters are called while
+// recovering from a panic, so a panic in the Reporter is recovered and logged
+// instead of crashing the Go runtime.
+func callReporter(fn Reporter, err error) {
+	defer CatchHandler(func(reporterErr error) {
+		jww.ERROR.Printf("Reporter panicked with %+v while reporting: %+v",
+			reporterErr, err)
+	})
+	fn(err)
+}
@@ -0,0 +1,209 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"math/rand"
+	"syscall/js"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Default values used by RetryPolicy when a field is not set.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+	DefaultBackoffFactor  = 2
+)
+
+// RetryPolicy retries calls into Javascript that throw or reject. Calls are
+// retried with exponential backoff and jitter until they succeed, return an
+// error that is not retryable, or reach the maximum number of attempts.
+//
+// Run and Await block while waiting between attempts, so they must not be
+// called on the Javascript event loop (i.e., directly inside a js.FuncOf). If
+// they are, they return ErrEventLoopBlocked instead of retrying.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the call is made, including
+	// the first attempt. If 0 or less, DefaultMaxAttempts is used.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the second attempt. If 0 or less,
+	// DefaultInitialBackoff is used.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum wait between attempts. If 0 or less,
+	// DefaultMaxBackoff is used.
+	MaxBackoff time.Duration
+
+	// BackoffFactor is the factor the wait is multiplied by after each
+	// attempt. If less than 1, DefaultBackoffFactor is used.
+	BackoffFactor float64
+
+	// Jitter is the fraction of the wait that is randomly added or removed so
+	// that many callers do not retry at the same time. It must be between 0
+	// and 1. If 0, there is no jitter.
+	Jitter float64
+
+	// RetryableNames are the names of the Javascript Error classes that are
+	// retried (e.g., "TimeoutError"). If empty, all Javascript exceptions are
+	// retried. Ignored if Retryable is set.
+	RetryableNames []string
+
+	// Retryable, if set, reports whether the error returned by an attempt
+	// should be retried. It overrides RetryableNames.
+	Retryable func(err error) bool
+
+	// Breaker, if set, is the CircuitBreaker each attempt is made through.
+	// Attempts rejected because the circuit is open are not retried.
+	Breaker *CircuitBreaker
+}
+
+// Run calls fn with RunAndCatch until it does not throw or the policy stops
+// retrying. Returns the value returned by fn or the error of the last attempt.
+func (p RetryPolicy) Run(fn func() js.Value) (js.Value, error) {
+	return p.retry(func() (js.Value, error) {
+		if p.Breaker != nil {
+			return p.Breaker.Run(fn)
+		}
+		return RunAndCatch(fn)
+	})
+}
+
+// Await calls fn and waits for the Promise it returns until it resolves or the
+// policy stops retrying. Returns the value the Promise resolves to or the
+// error of the last attempt. Exceptions thrown by fn and rejection reasons are
+// converted to Go errors in the same way as Catch.
+func (p RetryPolicy) Await(fn func() js.Value) (js.Value, error) {
+	return p.retry(func() (js.Value, error) {
+		if p.Breaker != nil {
+			return p.Breaker.Await(fn)
+		}
+		return awaitPromise(fn)
+	})
+}
+
+// retry makes attempts until one succeeds, its error is not retryable, or the
+// maximum number of attempts is reached.
+func (p RetryPolicy) retry(attempt func() (js.Value, error)) (js.Value, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = DefaultInitialBackoff
+	}
+
+	for i := 1; ; i++ {
+		v, err := attempt()
+		if err == nil {
+			return v, nil
+		} else if i >= maxAttempts || !p.retryable(err) {
+			return v, errors.WithMessagef(err, "failed after %d attempt(s)", i)
+		} else if blockErr := CheckBlocking("RetryPolicy"); blockErr != nil {
+			return v, blockErr
+		}
+
+		time.Sleep(p.jitter(backoff))
+		backoff = p.nextBackoff(backoff)
+	}
+}
+
+// retryable reports whether the error should be retried.
+func (p RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	} else if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	var jsErr *JSError
+	if !errors.As(err, &jsErr) {
+		return false
+	} else if len(p.RetryableNames) == 0 {
+		return true
+	}
+	for _, name := range p.RetryableNames {
+		if jsErr.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nextBackoff returns the wait before the next attempt.
+func (p RetryPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	factor := p.BackoffFactor
+	if factor < 1 {
+		factor = DefaultBackoffFactor
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+
+	backoff = time.Duration(float64(backoff) * factor)
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// jitter randomly adds or removes up to the Jitter fraction of the wait.
+func (p RetryPolicy) jitter(backoff time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return backoff
+	}
+	delta := p.Jitter * float64(backoff) * (2*rand.Float64() - 1)
+	return backoff + time.Duration(delta)
+}
+
+// awaitPromise calls fn and waits for the Promise it returns. Returns the
+// value it resolves to or its rejection reason converted to a Go error.
+// Returns ErrEventLoopBlocked if called on the Javascript event loop.
+func awaitPromise(fn func() js.Value) (js.Value, error) {
+	if err := CheckBlocking("awaiting a Promise"); err != nil {
+		return js.Value{}, err
+	}
+
+	promise, err := RunAndCatch(fn)
+	if err != nil {
+		return js.Value{}, err
+	}
+
+	type result struct {
+		v        js.Value
+		rejected bool
+	}
+	c := make(chan result, 1)
+	onResolve := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		c <- result{args[0], false}
+		return nil
+	})
+	defer onResolve.Release()
+	onReject := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		c <- result{args[0], true}
+		return nil
+	})
+	defer onReject.Release()
+
+	js.Global().Get("Promise").Call("resolve", promise).
+		Call("then", onResolve, onReject)
+
+	r := <-c
+	if r.rejected {
+		return js.Value{}, jsToGoError(r.v, nil, 0)
+	}
+	return r.v, nil
+}
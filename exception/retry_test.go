@@ -0,0 +1,93 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"syscall/js"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// flakyFunc returns a Javascript function that throws an Error of the given
+// class on the first n calls and returns "ok" after.
+func flakyFunc(class string, n int) js.Value {
+	return js.Global().Get("Function").New("name", "n", `let calls = 0
+		return () => {
+			if (calls++ < n) throw new globalThis[name]("attempt " + calls)
+			return "ok"
+		}`).Invoke(class, n)
+}
+
+// Tests that RetryPolicy.Run retries a function that throws until it succeeds.
+func TestRetryPolicy_Run(t *testing.T) {
+	fn := flakyFunc(TypeErrorClass, 2)
+	p := RetryPolicy{InitialBackoff: time.Millisecond, Jitter: 0.5}
+
+	v, err := p.Run(func() js.Value { return fn.Invoke() })
+	if err != nil {
+		t.Fatalf("Failed after retrying: %+v", err)
+	}
+	if v.String() != "ok" {
+		t.Errorf("Unexpected value.\nexpected: %s\nreceived: %s", "ok", v)
+	}
+}
+
+// Error path: Tests that RetryPolicy.Run returns the error of the last attempt
+// once the maximum number of attempts is reached and does not retry errors
+// whose name is not in RetryableNames.
+func TestRetryPolicy_Run_Failure(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	fn := flakyFunc(TypeErrorClass, 5)
+	_, err := p.Run(func() js.Value { return fn.Invoke() })
+	var jsErr *JSError
+	if !errors.As(err, &jsErr) || jsErr.Message != "attempt 2" {
+		t.Errorf("Unexpected error.\nexpected: %s\nreceived: %v",
+			"attempt 2", err)
+	}
+
+	p.RetryableNames = []string{RangeErrorClass}
+	fn = flakyFunc(TypeErrorClass, 5)
+	_, err = p.Run(func() js.Value { return fn.Invoke() })
+	if !errors.As(err, &jsErr) || jsErr.Message != "attempt 1" {
+		t.Errorf("Non-retryable error was retried: %v", err)
+	}
+}
+
+// Tests that RetryPolicy.Await retries a function whose Promise rejects until
+// it resolves.
+func TestRetryPolicy_Await(t *testing.T) {
+	fn := flakyFunc(TypeErrorClass, 1)
+	asyncFn := js.Global().Get("Function").New("fn", `return async () => fn()`).
+		Invoke(fn)
+	p := RetryPolicy{InitialBackoff: time.Millisecond}
+
+	v, err := p.Await(func() js.Value { return asyncFn.Invoke() })
+	if err != nil {
+		t.Fatalf("Failed after retrying: %+v", err)
+	}
+	if v.String() != "ok" {
+		t.Errorf("Unexpected value.\nexpected: %s\nreceived: %s", "ok", v)
+	}
+}
+
+// Tests that RetryPolicy stops retrying once its CircuitBreaker opens.
+func TestRetryPolicy_Breaker(t *testing.T) {
+	fn := flakyFunc(TypeErrorClass, 5)
+	p := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond,
+		Breaker: NewCircuitBreaker(2, time.Hour)}
+
+	_, err := p.Run(func() js.Value { return fn.Invoke() })
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Unexpected error.\nexpected: %v\nreceived: %v",
+			ErrCircuitOpen, err)
+	}
+}
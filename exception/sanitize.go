@@ -0,0 +1,138 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// Redaction replaces all matches of a regular expression in an error message.
+type Redaction struct {
+	// Pattern matches the text to redact.
+	Pattern *regexp.Regexp
+
+	// Replacement replaces each match. It can reference submatches using the
+	// syntax of regexp.Regexp.ReplaceAllString (e.g., "$1").
+	Replacement string
+}
+
+// SanitizePolicy controls how error messages and stack traces are sanitized
+// before they are exposed to Javascript, where they may be collected by
+// third-party telemetry.
+type SanitizePolicy struct {
+	// StripPaths removes the directories from absolute file paths, such as
+	// those in Go stack traces, leaving only the file name.
+	StripPaths bool
+
+	// Redactions are applied in order to redact secrets.
+	Redactions []Redaction
+
+	// MaxLength is the maximum length, in bytes, of a message. Longer messages
+	// are truncated. If 0 or less, messages are not truncated.
+	MaxLength int
+}
+
+// RedactedText replaces text removed by the default redactions.
+const RedactedText = "[REDACTED]"
+
+// truncatedSuffix is appended to messages truncated by a SanitizePolicy.
+const truncatedSuffix = "… (truncated)"
+
+var (
+	// pathRegex matches the directories of an absolute Unix or Windows file
+	// path that starts a line or follows whitespace or a parenthesis. URLs are
+	// not matched because their path follows a colon.
+	pathRegex = regexp.MustCompile(
+		`(^|[\s(])((?:[A-Za-z]:)?[/\\](?:[^/\\\s:()]+[/\\])+)`)
+
+	// DefaultRedactions redact hexadecimal strings of at least 32 characters,
+	// such as keys and hashes, and the values of secret fields, such as
+	// "password=..." or "token: ...".
+	DefaultRedactions = []Redaction{
+		{regexp.MustCompile(`\b(?:0x)?[0-9a-fA-F]{32,}\b`), RedactedText},
+		{regexp.MustCompile(`(?i)\b(password|passwd|secret|token|` +
+			`api[_-]?key|private[_-]?key)(\s*[:=]\s*)\S+`),
+			"$1$2" + RedactedText},
+	}
+
+	// DefaultSanitizePolicy is the policy used until one is set with
+	// SetSanitizePolicy.
+	DefaultSanitizePolicy = SanitizePolicy{
+		StripPaths: true,
+		Redactions: DefaultRedactions,
+		MaxLength:  4096,
+	}
+)
+
+// sanitizer contains the policy set by SetSanitizePolicy and the debug mode
+// set by SetDebug.
+var sanitizer = struct {
+	policy SanitizePolicy
+	debug  bool
+	mux    sync.RWMutex
+}{policy: DefaultSanitizePolicy}
+
+// SetSanitizePolicy sets the policy used to sanitize error messages and stack
+// traces by every function in this package that creates or throws a
+// Javascript error.
+func SetSanitizePolicy(p SanitizePolicy) {
+	sanitizer.mux.Lock()
+	defer sanitizer.mux.Unlock()
+	sanitizer.policy = p
+}
+
+// SetDebug enables or disables debug mode. In debug mode, error messages and
+// stack traces are exposed to Javascript unsanitized.
+func SetDebug(debug bool) {
+	sanitizer.mux.Lock()
+	defer sanitizer.mux.Unlock()
+	sanitizer.debug = debug
+}
+
+// Sanitize applies the policy set with SetSanitizePolicy to the message. It
+// returns the message unchanged in debug mode.
+func Sanitize(message string) string {
+	sanitizer.mux.RLock()
+	p, debug := sanitizer.policy, sanitizer.debug
+	sanitizer.mux.RUnlock()
+	if debug {
+		return message
+	}
+	return p.Sanitize(message)
+}
+
+// Sanitize applies the policy to the message.
+func (p SanitizePolicy) Sanitize(message string) string {
+	if p.StripPaths {
+		message = pathRegex.ReplaceAllString(message, "$1")
+	}
+	for _, r := range p.Redactions {
+		message = r.Pattern.ReplaceAllString(message, r.Replacement)
+	}
+	if p.MaxLength > 0 && len(message) > p.MaxLength {
+		message = truncate(message, p.MaxLength)
+	}
+	return message
+}
+
+// truncate shortens the message to at most n bytes, including the truncation
+// suffix, without splitting a UTF-8 character.
+func truncate(message string, n int) string {
+	end := n - len(truncatedSuffix)
+	if end < 0 {
+		end = 0
+	}
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end] + truncatedSuffix
+}
@@ -0,0 +1,87 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Tests that DefaultSanitizePolicy strips the directories of file paths but
+// not URLs and redacts secrets.
+func TestSanitizePolicy_Sanitize(t *testing.T) {
+	tests := map[string]string{
+		"main.run\n\t/home/user/project/main.go:12": "main.run\n\tmain.go:12",
+		`failed (C:\Users\me\app\file.go:3)`:        "failed (file.go:3)",
+		"at https://example.com/js/app.js:1:2":      "at https://example.com/js/app.js:1:2",
+		"invalid key 0123456789abcdef0123456789abcdef": "invalid key " +
+			RedactedText,
+		"login with password=hunter2 failed": "login with password=" +
+			RedactedText + " failed",
+		"API_KEY: abc123":              "API_KEY: " + RedactedText,
+		"short hex 0xdeadbeef is kept": "short hex 0xdeadbeef is kept",
+	}
+
+	for message, expected := range tests {
+		if s := DefaultSanitizePolicy.Sanitize(message); s != expected {
+			t.Errorf("Unexpected sanitized message for %q."+
+				"\nexpected: %q\nreceived: %q", message, expected, s)
+		}
+	}
+}
+
+// Tests that SanitizePolicy.Sanitize truncates long messages without splitting
+// a UTF-8 character.
+func TestSanitizePolicy_Sanitize_MaxLength(t *testing.T) {
+	p := SanitizePolicy{MaxLength: len(truncatedSuffix) + 4}
+
+	s := p.Sanitize("ab€" + strings.Repeat("c", 20))
+	if expected := "ab" + truncatedSuffix; s != expected {
+		t.Errorf("Unexpected truncated message."+
+			"\nexpected: %q\nreceived: %q", expected, s)
+	}
+	if s = p.Sanitize("abc"); s != "abc" {
+		t.Errorf("Short message modified: %q", s)
+	}
+}
+
+// Tests that NewError sanitizes the message using the policy set with
+// SetSanitizePolicy and that debug mode disables sanitization.
+func TestSetDebug(t *testing.T) {
+	SetSanitizePolicy(SanitizePolicy{Redactions: []Redaction{
+		{DefaultRedactions[1].Pattern, "$1$2***"}}})
+	defer SetSanitizePolicy(DefaultSanitizePolicy)
+
+	err := errors.New("token=secret")
+	if m := NewError(err).Get("message").String(); m != "token=***" {
+		t.Errorf("Message not sanitized.\nexpected: %s\nreceived: %s",
+			"token=***", m)
+	}
+
+	SetDebug(true)
+	defer SetDebug(false)
+	if m := NewError(err).Get("message").String(); m != err.Error() {
+		t.Errorf("Message sanitized in debug mode."+
+			"\nexpected: %s\nreceived: %s", err, m)
+	}
+}
+
+// Tests that NewTrace does not expose the absolute paths of the stack trace.
+func TestNewTrace_Sanitized(t *testing.T) {
+	m := NewTrace(errors.New("test error")).Get("message").String()
+	if !strings.Contains(m, "sanitize_test.go") {
+		t.Errorf("Stack trace missing from message:\n%s", m)
+	}
+	if strings.Contains(m, "/exception/sanitize_test.go") {
+		t.Errorf("Absolute path not removed from message:\n%s", m)
+	}
+}
@@ -0,0 +1,312 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"bytes"
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+)
+
+// stringifyError converts the value to JSON. Errors are converted with their
+// name, own properties, cause, and errors. Other values are converted with only
+// their own properties.
+func stringifyError(v js.Value) string {
+	stringify := js.Global().Get("JSON").Get("stringify")
+	if v.InstanceOf(Error) {
+		return stringify.Invoke(errorToPlain(v, 0)).String()
+	}
+	properties := js.Global().Get("Object").Call("getOwnPropertyNames", v)
+	return stringify.Invoke(v, properties).String()
+}
+
+// classProperty is the property of a serialized Error that records its class
+// when it cannot be found from its name, such as for a DOMException, whose name
+// is the name of the exception (e.g., "AbortError").
+const classProperty = "class"
+
+// errorToPlain converts the Error to a plain object containing its name,
+// message, code, own properties, cause, and errors so that it can be converted
+// to JSON. The name, message, and code are copied explicitly since they may be
+// inherited, such as the getters on DOMException. A DOMException is marked with
+// its class. Values that are not Errors are returned as is.
+func errorToPlain(e js.Value, depth int) js.Value {
+	if !e.InstanceOf(Error) || depth > maxCauseDepth {
+		return e
+	}
+
+	object := js.Global().Get("Object")
+	o := object.New()
+	names := object.Call("getOwnPropertyNames", e)
+	for i := 0; i < names.Length(); i++ {
+		name := names.Index(i).String()
+		o.Set(name, e.Get(name))
+	}
+	o.Set("name", e.Get("name"))
+	o.Set("message", e.Get("message"))
+	if hasProperty(e, "code") {
+		o.Set("code", e.Get("code"))
+	}
+	if c := js.Global().Get(DOMExceptionClass); c.Type() == js.TypeFunction &&
+		e.InstanceOf(c) {
+		o.Set(classProperty, DOMExceptionClass)
+	}
+	if hasProperty(e, "cause") {
+		o.Set("cause", errorToPlain(e.Get("cause"), depth+1))
+	}
+	if errs := e.Get("errors"); isArray(errs) {
+		plain := make([]any, errs.Length())
+		for i := range plain {
+			plain[i] = errorToPlain(errs.Index(i), depth+1)
+		}
+		o.Set("errors", plain)
+	}
+	return o
+}
+
+// reviveError reconstructs an Error of the named class from the parsed JSON
+// with its stack, cause chain, and custom properties. Classes are looked up
+// with GetClass, so custom classes registered with RegisterClass are
+// supported. Objects marked as a DOMException are revived as a DOMException
+// with the name. Values that are not objects are returned as is.
+func reviveError(o js.Value, depth int) js.Value {
+	if o.Type() != js.TypeObject || isArray(o) || depth > maxCauseDepth {
+		return o
+	}
+
+	object := js.Global().Get("Object")
+	name, message, stack := o.Get("name"), o.Get("message"), o.Get("stack")
+	errs := o.Get("errors")
+
+	class := Error
+	if name.Type() == js.TypeString {
+		if c, exists := GetClass(name.String()); exists {
+			class = c
+		}
+	}
+
+	var opts any = js.Undefined()
+	if hasProperty(o, "cause") {
+		opts = map[string]any{"cause": reviveError(o.Get("cause"), depth+1)}
+	}
+	if isArray(errs) {
+		revived := make([]any, errs.Length())
+		for i := range revived {
+			revived[i] = reviveError(errs.Index(i), depth+1)
+		}
+		errs = js.ValueOf(revived)
+	}
+
+	var e js.Value
+	domException := js.Global().Get(DOMExceptionClass)
+	isDOMException := domException.Type() == js.TypeFunction &&
+		o.Get(classProperty).Equal(js.ValueOf(DOMExceptionClass))
+	if isDOMException {
+		// The DOMException constructor takes the name in place of options, so
+		// the cause is defined afterward
+		e = domException.New(message, name)
+		if hasProperty(o, "cause") {
+			object.Call("defineProperty", e, "cause", map[string]any{
+				"value": opts.(map[string]any)["cause"], "writable": true,
+				"configurable": true})
+		}
+	} else if aggregate := js.Global().Get("AggregateError"); class.Equal(aggregate) {
+		if errs.IsUndefined() {
+			errs = js.ValueOf([]any{})
+		}
+		e = class.New(errs, message, opts)
+	} else {
+		e = class.New(message, opts)
+		if !errs.IsUndefined() {
+			e.Set("errors", errs)
+		}
+	}
+
+	if name.Type() == js.TypeString && e.Get("name").String() != name.String() {
+		object.Call("defineProperty", e, "name", map[string]any{
+			"value": name, "writable": true, "configurable": true})
+	}
+	if !stack.IsUndefined() {
+		object.Call("defineProperty", e, "stack", map[string]any{
+			"value": stack, "writable": true, "configurable": true})
+	}
+
+	keys := object.Call("keys", o)
+	for i := 0; i < keys.Length(); i++ {
+		switch key := keys.Index(i).String(); key {
+		case "name", "message", "stack", "cause", "errors", classProperty:
+		case "code":
+			// The code of a DOMException is derived from its name
+			if !isDOMException {
+				e.Set(key, o.Get(key))
+			}
+		default:
+			e.Set(key, o.Get(key))
+		}
+	}
+	return e
+}
+
+// hasProperty returns true if the object or its prototype chain has the
+// property, like the Javascript in operator.
+func hasProperty(o js.Value, name string) bool {
+	return js.Global().Get("Reflect").Call("has", o, name).Bool()
+}
+
+// isArray returns true if the value is a Javascript array.
+func isArray(v js.Value) bool {
+	return js.Global().Get("Array").Call("isArray", v).Bool()
+}
+
+// JsonToJsError reconstructs a Javascript Error from JSON created by
+// JsErrorToJson or by marshalling an ErrorData. The Error is of the class
+// named by the name field if it exists on globalThis, otherwise it is an Error
+// with that name. A DOMException is reconstructed as a DOMException with the
+// same name. Its stack, cause chain, errors, and custom properties are
+// restored.
+//
+// Returns an error if the JSON is invalid or is not an object.
+func JsonToJsError(jsonStr string) (js.Value, error) {
+	parsed, err := RunAndCatch(func() js.Value {
+		return js.Global().Get("JSON").Call("parse", jsonStr)
+	})
+	if err != nil {
+		return js.Value{}, errors.Wrap(err, "failed to parse error JSON")
+	} else if parsed.Type() != js.TypeObject || isArray(parsed) {
+		return js.Value{}, errors.Errorf(
+			"error JSON is not an object: %s", jsonStr)
+	}
+
+	return reviveError(parsed, 0), nil
+}
+
+// ErrorData is the Go representation of a serialized Javascript Error. It is
+// marshalled to and from the same JSON as JsErrorToJson and JsonToJsError so
+// that errors can be stored or sent through postMessage and rethrown.
+//
+// ErrorData is a Go error. Its Unwrap method returns its cause.
+type ErrorData struct {
+	// Name is the name of the Error class (e.g., "TypeError").
+	Name string
+
+	// Message is the error message.
+	Message string
+
+	// Stack is the unparsed Javascript stack trace.
+	Stack string
+
+	// Cause is the cause of the Error. Nil if the Error has no cause.
+	Cause *ErrorData
+
+	// Errors are the errors of an AggregateError.
+	Errors []*ErrorData
+
+	// Properties contains all other properties of the Error, such as code,
+	// details, and the class marker of a DOMException.
+	Properties map[string]any
+}
+
+// NewErrorData converts the Javascript Error to an ErrorData.
+func NewErrorData(value js.Value) (*ErrorData, error) {
+	var ed ErrorData
+	if err := json.Unmarshal([]byte(JsErrorToJson(value)), &ed); err != nil {
+		return nil, err
+	}
+	return &ed, nil
+}
+
+// JS reconstructs the Javascript Error represented by the ErrorData.
+func (ed *ErrorData) JS() (js.Value, error) {
+	data, err := json.Marshal(ed)
+	if err != nil {
+		return js.Value{}, err
+	}
+	return JsonToJsError(string(data))
+}
+
+// Error returns the error message.
+func (ed *ErrorData) Error() string { return ed.Message }
+
+// Unwrap returns the cause of the Error.
+func (ed *ErrorData) Unwrap() error {
+	if ed.Cause == nil {
+		return nil
+	}
+	return ed.Cause
+}
+
+// MarshalJSON marshals the ErrorData into a JSON object containing the custom
+// properties alongside the name, message, stack, cause, and errors.
+func (ed *ErrorData) MarshalJSON() ([]byte, error) {
+	o := make(map[string]any, len(ed.Properties)+5)
+	for k, v := range ed.Properties {
+		o[k] = v
+	}
+	o["name"] = ed.Name
+	o["message"] = ed.Message
+	if ed.Stack != "" {
+		o["stack"] = ed.Stack
+	}
+	if ed.Cause != nil {
+		o["cause"] = ed.Cause
+	}
+	if ed.Errors != nil {
+		o["errors"] = ed.Errors
+	}
+	return json.Marshal(o)
+}
+
+// UnmarshalJSON unmarshalls the JSON object into the ErrorData. Values that
+// are not objects, such as a string thrown as a cause, are unmarshalled with
+// their JSON as the message.
+func (ed *ErrorData) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			s = string(data)
+		}
+		*ed = ErrorData{Message: s}
+		return nil
+	}
+
+	var fields struct {
+		Name    string       `json:"name"`
+		Message string       `json:"message"`
+		Stack   string       `json:"stack"`
+		Cause   *ErrorData   `json:"cause"`
+		Errors  []*ErrorData `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var properties map[string]any
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+	for _, k := range []string{"name", "message", "stack", "cause", "errors"} {
+		delete(properties, k)
+	}
+	if len(properties) == 0 {
+		properties = nil
+	}
+
+	*ed = ErrorData{
+		Name:       fields.Name,
+		Message:    fields.Message,
+		Stack:      fields.Stack,
+		Cause:      fields.Cause,
+		Errors:     fields.Errors,
+		Properties: properties,
+	}
+	return nil
+}
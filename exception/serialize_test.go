@@ -0,0 +1,182 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"encoding/json"
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+// Tests that an Error with a custom class, custom properties, and a cause
+// chain is reconstructed by JsonToJsError from the JSON created by
+// JsErrorToJson.
+func TestJsonToJsError(t *testing.T) {
+	name := "TestJsonToJsErrorError"
+	class, err := RegisterClass(name, nil)
+	if err != nil {
+		t.Fatalf("Failed to register class: %+v", err)
+	}
+
+	cause := js.Global().Get(RangeErrorClass).New("root cause")
+	original := class.New("top error", map[string]any{"cause": cause})
+	original.Set("code", "E_TOP")
+	original.Set("details", map[string]any{"id": 5})
+
+	jsErr, err := JsonToJsError(JsErrorToJson(original))
+	if err != nil {
+		t.Fatalf("Failed to convert JSON to error: %+v", err)
+	}
+
+	if !jsErr.InstanceOf(class) {
+		t.Errorf("Error is not an instance of %s.", name)
+	}
+	for _, p := range []string{"name", "message", "stack", "code"} {
+		if jsErr.Get(p).String() != original.Get(p).String() {
+			t.Errorf("Incorrect %s.\nexpected: %s\nreceived: %s",
+				p, original.Get(p).String(), jsErr.Get(p).String())
+		}
+	}
+	if id := jsErr.Get("details").Get("id").Int(); id != 5 {
+		t.Errorf("Incorrect details.\nexpected: %d\nreceived: %d", 5, id)
+	}
+
+	c := jsErr.Get("cause")
+	if !c.InstanceOf(js.Global().Get(RangeErrorClass)) {
+		t.Errorf("Cause is not a %s: %s", RangeErrorClass, JsErrorToJson(c))
+	} else if c.Get("stack").String() != cause.Get("stack").String() {
+		t.Errorf("Incorrect cause stack.\nexpected: %s\nreceived: %s",
+			cause.Get("stack").String(), c.Get("stack").String())
+	}
+}
+
+// Tests that JsonToJsError reconstructs the errors of an AggregateError and
+// keeps the name of unknown classes.
+func TestJsonToJsError_AggregateError(t *testing.T) {
+	unknown := Error.New("unknown")
+	unknown.Set("name", "UnknownClassError")
+	aggregate, _ := GetClass(AggregateErrorClass)
+	original := aggregate.New([]any{unknown, Error.New("second")}, "multiple")
+
+	jsErr, err := JsonToJsError(JsErrorToJson(original))
+	if err != nil {
+		t.Fatalf("Failed to convert JSON to error: %+v", err)
+	}
+
+	if !jsErr.InstanceOf(aggregate) {
+		t.Fatalf("Error is not an AggregateError: %s", JsErrorToJson(jsErr))
+	}
+	errs := jsErr.Get("errors")
+	if errs.Length() != 2 {
+		t.Fatalf("Incorrect number of errors.\nexpected: %d\nreceived: %d",
+			2, errs.Length())
+	}
+	if n := errs.Index(0).Get("name").String(); n != "UnknownClassError" {
+		t.Errorf("Incorrect name.\nexpected: %s\nreceived: %s",
+			"UnknownClassError", n)
+	}
+	if m := errs.Index(1).Get("message").String(); m != "second" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s", "second", m)
+	}
+}
+
+// Tests that a DOMException, whose name, message, and code are inherited
+// getters, is reconstructed by JsonToJsError as a DOMException with the same
+// name, message, and code.
+func TestJsonToJsError_DOMException(t *testing.T) {
+	class := js.Global().Get(DOMExceptionClass)
+	if class.Type() != js.TypeFunction {
+		t.Skipf("%s is not supported in this environment.", DOMExceptionClass)
+	}
+	original := class.New("operation cancelled", AbortErrorName)
+
+	data := JsErrorToJson(original)
+	jsErr, err := JsonToJsError(data)
+	if err != nil {
+		t.Fatalf("Failed to convert JSON to error: %+v", err)
+	}
+
+	if !jsErr.InstanceOf(class) {
+		t.Errorf("Error is not a %s: %s", DOMExceptionClass, data)
+	}
+	for _, p := range []string{"name", "message", "code"} {
+		if jsErr.Get(p).String() != original.Get(p).String() {
+			t.Errorf("Incorrect %s.\nexpected: %s\nreceived: %s",
+				p, original.Get(p).String(), jsErr.Get(p).String())
+		}
+	}
+}
+
+// Error path: Tests that JsonToJsError returns an error for invalid JSON and
+// for JSON that is not an object.
+func TestJsonToJsError_InvalidJson(t *testing.T) {
+	for _, s := range []string{"{invalid", `"string"`, "[]", "null"} {
+		if _, err := JsonToJsError(s); err == nil {
+			t.Errorf("No error for JSON %s.", s)
+		}
+	}
+}
+
+// Tests that an ErrorData created with NewErrorData contains the fields and
+// properties of the Error and that converting it back to Javascript and to
+// JSON again results in the same ErrorData.
+func TestErrorData(t *testing.T) {
+	cause := js.Global().Get(TypeErrorClass).New("cause")
+	original := Error.New("top", map[string]any{"cause": cause})
+	original.Set("code", "E_TOP")
+
+	ed, err := NewErrorData(original)
+	if err != nil {
+		t.Fatalf("Failed to create ErrorData: %+v", err)
+	}
+
+	expected := &ErrorData{
+		Name:    ErrorClass,
+		Message: "top",
+		Stack:   original.Get("stack").String(),
+		Cause: &ErrorData{Name: TypeErrorClass, Message: "cause",
+			Stack: cause.Get("stack").String()},
+		Properties: map[string]any{"code": "E_TOP"},
+	}
+	if !reflect.DeepEqual(expected, ed) {
+		t.Errorf("Unexpected ErrorData.\nexpected: %+v\nreceived: %+v",
+			expected, ed)
+	}
+	if ed.Unwrap() != ed.Cause {
+		t.Errorf("Unwrap did not return the cause.")
+	}
+
+	jsErr, err := ed.JS()
+	if err != nil {
+		t.Fatalf("Failed to convert ErrorData to Javascript: %+v", err)
+	}
+	ed2, err := NewErrorData(jsErr)
+	if err != nil {
+		t.Fatalf("Failed to create ErrorData: %+v", err)
+	}
+	if !reflect.DeepEqual(ed, ed2) {
+		t.Errorf("ErrorData changed after round trip."+
+			"\nexpected: %+v\nreceived: %+v", ed, ed2)
+	}
+
+	data, err := json.Marshal(ed)
+	if err != nil {
+		t.Fatalf("Failed to marshal ErrorData: %+v", err)
+	}
+	var ed3 ErrorData
+	if err = json.Unmarshal(data, &ed3); err != nil {
+		t.Fatalf("Failed to unmarshal ErrorData: %+v", err)
+	}
+	if !reflect.DeepEqual(*ed, ed3) {
+		t.Errorf("ErrorData changed after JSON round trip."+
+			"\nexpected: %+v\nreceived: %+v", *ed, ed3)
+	}
+}
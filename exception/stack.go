@@ -0,0 +1,99 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxPanicDepth is the maximum number of frames captured by panicStack.
+const maxPanicDepth = 64
+
+// panicStack returns the stack trace of the panicking goroutine starting at
+// the function that called panic. It must be called from a deferred function
+// while the goroutine is panicking, before the stack is unwound. If the
+// goroutine is not panicking, the stack trace of the caller is returned.
+func panicStack() errors.StackTrace {
+	var pcs [maxPanicDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+
+	start := 0
+	for i, pc := range pcs[:n] {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil &&
+			fn.Name() == "runtime.gopanic" {
+			start = i + 1
+			break
+		}
+	}
+
+	// Skip the runtime functions that raise runtime errors, such as
+	// runtime.panicmem and runtime.sigpanic
+	for start > 0 && start < n {
+		fn := runtime.FuncForPC(pcs[start] - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+			break
+		}
+		start++
+	}
+
+	st := make(errors.StackTrace, n-start)
+	for i, pc := range pcs[start:n] {
+		st[i] = errors.Frame(pc)
+	}
+	return st
+}
+
+// stackTracer is implemented by errors that record a stack trace, such as
+// those created by github.com/pkg/errors.
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// stackError is an error recovered from a panic with the stack trace of where
+// the panic occurred. If the panic value was an error, it is wrapped and can be
+// inspected with errors.Unwrap, errors.Is, and errors.As.
+type stackError struct {
+	msg   string
+	err   error
+	stack errors.StackTrace
+}
+
+// Error returns the error message.
+func (e *stackError) Error() string { return e.msg }
+
+// Unwrap returns the error the panic was raised with, if any.
+func (e *stackError) Unwrap() error { return e.err }
+
+// StackTrace returns the stack trace of where the panic occurred. It
+// implements the stackTracer interface used by github.com/pkg/errors.
+func (e *stackError) StackTrace() errors.StackTrace { return e.stack }
+
+// Format implements fmt.Formatter. The verbs %s and %v print the error message
+// and %q prints it quoted. The flag %+v additionally prints the stack trace.
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.msg)
+			e.stack.Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, e.msg)
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.msg)
+	}
+}
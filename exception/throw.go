@@ -9,22 +9,112 @@
 
 package exception
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+)
+
+// throwHook is the function registered with SetThrowHook.
+var throwHook = struct {
+	fn  func(err error)
+	mux sync.RWMutex
+}{}
+
+// SetThrowHook registers a function that is called with the error before it is
+// thrown by Throw, Throwf, ThrowTrace, ThrowType, ThrowTypef, ThrowValue, or
+// ThrowDOMException. Errors thrown with a format are passed as a new error
+// containing the formatted message and Javascript values are passed as a
+// *JSError.
+// Set to nil to remove the hook.
+//
+// The hook is called before the exception is thrown, so it can record the
+// error before the Go runtime stops.
+func SetThrowHook(fn func(err error)) {
+	throwHook.mux.Lock()
+	defer throwHook.mux.Unlock()
+	throwHook.fn = fn
+}
+
+// callThrowHook calls the function registered with SetThrowHook, if there is
+// one.
+func callThrowHook(err error) {
+	throwHook.mux.RLock()
+	fn := throwHook.fn
+	throwHook.mux.RUnlock()
+	if fn != nil {
+		fn(err)
+	}
+}
 
 // Throw creates a Javascript Error object from a Go error and throws it as an
-// exception.
+// exception. If a mapping is registered for the error, the Error is of the
+// mapped class and has its code and details.
 func Throw(err error) {
-	throw("Error", err.Error())
+	callThrowHook(err)
+	class, properties := errorProperties(err)
+	throw(class, err.Error(), propertiesJson(properties))
 }
 
 // Throwf formats according to a format specifier, creates a Javascript Error
 // object, and throws it as an exception.
 func Throwf(format string, a ...any) {
-	throw("Error", fmt.Sprintf(format, a...))
+	message := fmt.Sprintf(format, a...)
+	callThrowHook(errors.New(message))
+	throw(ErrorClass, message, "{}")
 }
 
 // ThrowTrace creates a Javascript Error object from a Go error and throws it as
-// an exception. The error includes its stack trace.
+// an exception. The error includes its stack trace. If a mapping is registered
+// for the error, the Error is of the mapped class and has its code and details.
 func ThrowTrace(err error) {
-	throw("Error", fmt.Sprintf("%+v", err))
+	callThrowHook(err)
+	class, properties := errorProperties(err)
+	throw(class, fmt.Sprintf("%+v", err), propertiesJson(properties))
+}
+
+// ThrowType creates a Javascript object of the given Error class from a Go
+// error and throws it as an exception. The class can be a built-in Error class,
+// such as TypeErrorClass, or a custom class registered with RegisterClass. If
+// the class does not exist, a plain Error is thrown. The class overrides the
+// class of any mapping registered for the error, but the mapping's code and
+// details are still set.
+func ThrowType(class string, err error) {
+	callThrowHook(err)
+	_, properties := errorProperties(err)
+	throw(classOrError(class), err.Error(), propertiesJson(properties))
+}
+
+// ThrowTypef formats according to a format specifier, creates a Javascript
+// object of the given Error class, and throws it as an exception.
+func ThrowTypef(class, format string, a ...any) {
+	message := fmt.Sprintf(format, a...)
+	callThrowHook(errors.New(message))
+	throw(classOrError(class), message, "{}")
+}
+
+// ThrowValue throws the Javascript value as an exception. The value can be a
+// pre-built Error with custom properties or any other value, such as a string
+// or a DOMException.
+func ThrowValue(v js.Value) {
+	callThrowHook(jsToGoError(v, nil, 0))
+	throwValue(v)
+}
+
+// ThrowDOMException creates a DOMException with the name and message and
+// throws it as an exception. Use the DOMException names defined in this
+// package, such as AbortErrorName, to follow Web platform conventions.
+func ThrowDOMException(name, message string) {
+	ThrowValue(NewDOMException(name, message))
+}
+
+// classOrError returns the class name if it is a known Error class. Otherwise,
+// it returns ErrorClass.
+func classOrError(class string) string {
+	if _, exists := GetClass(class); !exists {
+		return ErrorClass
+	}
+	return class
 }
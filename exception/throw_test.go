@@ -0,0 +1,33 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Tests that the function registered with SetThrowHook is called by
+// callThrowHook and that it is no longer called once removed.
+func TestSetThrowHook(t *testing.T) {
+	var received []error
+	SetThrowHook(func(err error) { received = append(received, err) })
+
+	expected := errors.New("thrown error")
+	callThrowHook(expected)
+	SetThrowHook(nil)
+	callThrowHook(expected)
+
+	if len(received) != 1 || received[0] != expected {
+		t.Errorf("Unexpected errors passed to hook.\nexpected: %v\nreceived: %v",
+			[]error{expected}, received)
+	}
+}
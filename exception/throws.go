@@ -5,38 +5,113 @@
 // LICENSE file.                                                              //
 ////////////////////////////////////////////////////////////////////////////////
 
+//go:build js && wasm
+
 package exception
 
-// This file contains the stub for the throw function, which is linked via
-// assembly in throw_js.s to a custom function added to wasm_exec.js that throws
-// the passed elements. This adds the ability to throw a Javascript exception
-// from the go webassembly.
-//
-// Testing uses the [wasmbrowsertest], which uses the default wasm_exec.js file,
-// which causes compile-time errors. To avoid this, throw_js.s must be cleared
-// out and the stub below must be replaced with an actual function, as shown
-// below.
-//
-//  func throw(exception string, message string) {}
-//
-// To make it easier, you can also replace throws.go temporarily with throws.dev
-// while running tests, which replaces the stub with a function that panics.
-//
-// You can also add the following lines to your Makefile.
+// This file contains the throw functions used to throw Javascript exceptions
+// from Go. They only use syscall/js and work with the stock wasm_exec.js
+// provided by Go (and with [wasmbrowsertest], which uses it), so the same
+// binary can be run with any loader.
 //
-//  wasmException = "vendor/gitlab.com/elixxir/wasm-utils/exception"
-//
-//  wasm_tests:
-//      cp $(wasmException)/throw_js.s $(wasmException)/throw_js.s.bak
-//      cp $(wasmException)/throws.go $(wasmException)/throws.go.bak
-//      > $(wasmException)/throw_js.s
-//      cp $(wasmException)/throws.dev $(wasmException)/throws.go
-//      -GOOS=js GOARCH=wasm go test -v ./...
-//      mv $(wasmException)/throw_js.s.bak $(wasmException)/throw_js.s
-//      mv $(wasmException)/throws.go.bak $(wasmException)/throws.go
+// An exception is thrown by reading a property of a Javascript object whose
+// getter throws it. Property reads are not wrapped in a try/catch in
+// wasm_exec.js, so the exception propagates out of the WebAssembly module. The
+// getter is a Javascript function that throws its argument. At startup, it
+// checks whether the loader defines one in the global throwGlobal, which our
+// modified wasm_exec.js does. Otherwise, it is created with the Function
+// constructor the first time an exception is thrown, which requires a
+// Content-Security-Policy that allows 'unsafe-eval'.
 //
 // [wasmbrowsertest]: https://github.com/agnivade/wasmbrowsertest
 
-// throw is a function stub that connects to the bindings in wasm_exec.js to
-// allow throwing exceptions.
-func throw(exception string, message string)
+import (
+	"sync"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// throwGlobal is the name of the global Javascript function, defined by our
+// modified wasm_exec.js, that throws its argument.
+const throwGlobal = "wasmUtilsThrow"
+
+func init() {
+	if js.Global().Get(throwGlobal).Type() == js.TypeFunction {
+		jww.DEBUG.Print("Throwing exceptions using the wasm_exec.js throw " +
+			"function.")
+	} else {
+		jww.DEBUG.Print("Throwing exceptions using a function created on " +
+			"first use.")
+	}
+}
+
+// throw throws a new Javascript object of the exception class with the message.
+// The properties are a JSON object whose fields are set on the thrown object.
+// The message is sanitized with Sanitize.
+func throw(exception, message, properties string) {
+	throwJS(newException(exception, Sanitize(message), properties))
+}
+
+// throwValue throws the Javascript value as an exception.
+func throwValue(v js.Value) {
+	throwJS(v)
+}
+
+// raiser contains the Javascript function that throws its argument, which is
+// created once on first use by getRaise.
+var raiser struct {
+	fn   js.Value
+	err  error
+	once sync.Once
+}
+
+// getRaise returns a Javascript function that throws its argument. Returns an
+// error if the loader does not define one and it cannot be created (e.g., due
+// to a Content-Security-Policy).
+func getRaise() (js.Value, error) {
+	raiser.once.Do(func() { raiser.fn, raiser.err = newRaise() })
+	return raiser.fn, raiser.err
+}
+
+// newRaise returns the function defined by the loader in throwGlobal, if it
+// exists. Otherwise, it creates one with the Function constructor.
+func newRaise() (fn js.Value, err error) {
+	if fn = js.Global().Get(throwGlobal); fn.Type() == js.TypeFunction {
+		return fn, nil
+	}
+	defer Catch(&err)
+	return js.Global().Get("Function").New("value", "throw value"), nil
+}
+
+// newException creates a new Javascript object of the exception class with the
+// message. The properties are a JSON object whose fields are set on the object.
+func newException(exception, message, properties string) js.Value {
+	v := js.Global().Get(exception).New(message)
+	js.Global().Get("Object").Call(
+		"assign", v, js.Global().Get("JSON").Call("parse", properties))
+	return v
+}
+
+// newThrower returns a Javascript object whose throw property throws the value
+// when read. The getter is the raise function bound to the value.
+func newThrower(raise, v js.Value) js.Value {
+	thrower := js.Global().Get("Object").New()
+	js.Global().Get("Reflect").Call("defineProperty", thrower, "throw",
+		map[string]any{"get": raise.Call("bind", nil, v)})
+	return thrower
+}
+
+// throwJS throws the Javascript value as an exception. If no function is
+// available to throw it, it panics with the value as a js.Error instead.
+func throwJS(v js.Value) {
+	raise, err := getRaise()
+	if err != nil {
+		jww.ERROR.Printf("Failed to throw Javascript exception: %+v",
+			errors.Wrapf(err, "no function to throw; define %s in the loader",
+				throwGlobal))
+		panic(js.Error{Value: v})
+	}
+	newThrower(raise, v).Get("throw")
+}
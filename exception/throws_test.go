@@ -0,0 +1,63 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package exception
+
+import (
+	"errors"
+	"syscall/js"
+	"testing"
+)
+
+// Tests that newException creates an Error of the given class with the
+// properties and that reading the throw property of newThrower throws it.
+func Test_newThrower(t *testing.T) {
+	v := newException(RangeErrorClass, "out of range", `{"code":"E_RANGE"}`)
+	raise, err := getRaise()
+	if err != nil {
+		t.Fatalf("Failed to get raise function: %+v", err)
+	}
+	thrower := newThrower(raise, v)
+
+	_, err = RunAndCatch(func() js.Value {
+		return js.Global().Get("Reflect").Call("get", thrower, "throw")
+	})
+	var jsErr *JSError
+	if !errors.As(err, &jsErr) {
+		t.Fatalf("Reading throw property did not throw: %+v", err)
+	} else if !jsErr.Value.Equal(v) {
+		t.Fatalf("Thrown value does not match the value.")
+	}
+	if !v.InstanceOf(js.Global().Get(RangeErrorClass)) {
+		t.Errorf("Thrown value is not a %s.", RangeErrorClass)
+	}
+	if v.Get("message").String() != "out of range" {
+		t.Errorf("Incorrect message.\nexpected: %s\nreceived: %s",
+			"out of range", v.Get("message").String())
+	}
+	if v.Get("code").String() != "E_RANGE" {
+		t.Errorf("Incorrect code.\nexpected: %s\nreceived: %s",
+			"E_RANGE", v.Get("code").String())
+	}
+}
+
+// Tests that newRaise returns the function defined by the loader when it
+// exists.
+func Test_newRaise_Loader(t *testing.T) {
+	loaderRaise := js.Global().Get("Function").New("value", "throw value")
+	js.Global().Set(throwGlobal, loaderRaise)
+	defer js.Global().Delete(throwGlobal)
+
+	raise, err := newRaise()
+	if err != nil {
+		t.Fatalf("Failed to get raise function: %+v", err)
+	} else if !raise.Equal(loaderRaise) {
+		t.Errorf("Function defined by the loader not used.")
+	}
+}
@@ -1,10 +1,10 @@
 module gitlab.com/elixxir/wasm-utils
 
-go 1.19
+go 1.20
 
 require (
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/jwalterweatherman v1.1.0
 )
 
-require github.com/Max-Sum/base32768 v0.0.0-20230304063302-18e6ce5945fd // indirect
+require github.com/Max-Sum/base32768 v0.0.0-20230304063302-18e6ce5945fd
@@ -0,0 +1,298 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"syscall/js"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/wasm-utils/exception"
+)
+
+// KeyErrors contains the errors for each key that failed in a batch operation.
+type KeyErrors map[string]error
+
+// Error returns each key name and its error, sorted by key name.
+func (ke KeyErrors) Error() string {
+	keys := make([]string, 0, len(ke))
+	for keyName := range ke {
+		keys = append(keys, keyName)
+	}
+	sort.Strings(keys)
+
+	errs := make([]string, len(keys))
+	for i, keyName := range keys {
+		errs[i] = keyName + ": " + ke[keyName].Error()
+	}
+	return fmt.Sprintf(
+		"failed for %d keys: %s", len(keys), strings.Join(errs, "; "))
+}
+
+// GetMany decodes and returns the values of all the given keys using a single
+// call to Javascript. Values that were successfully retrieved are returned even
+// if others failed. If any key fails, a KeyErrors is returned containing the
+// error for each failed key, which is os.ErrNotExist for keys that do not
+// exist.
+func (ls *localStorage) GetMany(keys []string) (map[string][]byte, error) {
+	prefixed := make([]string, len(keys))
+	for i, keyName := range keys {
+		prefixed[i] = ls.prefix + keyName
+	}
+
+	items, err := ls.v.GetItems(prefixed)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]byte, len(keys))
+	keyErrs := make(KeyErrors)
+	for i, keyName := range keys {
+		if items[i].Err != nil {
+			keyErrs[keyName] = items[i].Err
+		} else if value, err := decodeValue(keyName, items[i].Value); err != nil {
+			keyErrs[keyName] = err
+		} else {
+			values[keyName] = value
+		}
+	}
+
+	if len(keyErrs) > 0 {
+		return values, keyErrs
+	}
+	return values, nil
+}
+
+// SetMany encodes all the values and adds them to local storage using a single
+// call to Javascript. If any key fails to be set, such as when the local
+// storage quota has been reached, the remaining keys are still set and a
+// KeyErrors is returned containing the error for each failed key.
+func (ls *localStorage) SetMany(values map[string][]byte) error {
+	keys := make([]string, 0, len(values))
+	for keyName := range values {
+		keys = append(keys, keyName)
+	}
+	sort.Strings(keys)
+
+	prefixed := make([]string, len(keys))
+	encoded := make([]string, len(keys))
+	for i, keyName := range keys {
+		prefixed[i] = ls.prefix + keyName
+		encoded[i] = encodeValue(values[keyName])
+	}
+
+	errs, err := ls.v.SetItems(prefixed, encoded)
+	if err != nil {
+		return err
+	}
+
+	keyErrs := make(KeyErrors)
+	for i, keyName := range keys {
+		if errs[i] != nil {
+			keyErrs[keyName] = errs[i]
+		}
+	}
+
+	if len(keyErrs) > 0 {
+		return keyErrs
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Javascript Wrappers                                                        //
+////////////////////////////////////////////////////////////////////////////////
+
+// batchFuncs contains the Javascript functions used by GetItems and SetItems.
+// They are created once on first use by getBatchFuncs.
+var batchFuncs struct {
+	// getItems gets the values of multiple keys from storage in a single
+	// call. The key names are passed in as JSON to avoid a call per array
+	// element. It returns the results as a JSON string, where each result is
+	// either [value] or [null] if the key does not exist, and a list of
+	// [index, exception] pairs for each key where getItem threw an exception.
+	getItems js.Value
+
+	// setItems sets the values of multiple keys in storage in a single call.
+	// The key names and values are passed in as JSON. It returns a list of
+	// [index, exception] pairs for each key where setItem threw an exception.
+	setItems js.Value
+
+	err  error
+	once sync.Once
+}
+
+// getBatchFuncs returns the Javascript functions used by GetItems and
+// SetItems. They are created with the Function constructor, so an error is
+// returned if it is blocked by a Content-Security-Policy without
+// 'unsafe-eval'.
+func getBatchFuncs() (getItems, setItems js.Value, err error) {
+	batchFuncs.once.Do(func() {
+		batchFuncs.getItems, batchFuncs.setItems, batchFuncs.err =
+			newBatchFuncs()
+		if batchFuncs.err != nil {
+			jww.WARN.Printf("Batch storage functions unavailable; getting and "+
+				"setting keys individually: %+v", batchFuncs.err)
+		}
+	})
+	return batchFuncs.getItems, batchFuncs.setItems, batchFuncs.err
+}
+
+// newBatchFuncs creates the Javascript functions used by GetItems and
+// SetItems.
+func newBatchFuncs() (getItems, setItems js.Value, err error) {
+	defer exception.Catch(&err)
+	getItems = js.Global().Get("Function").New("storage", "keysJson",
+		`const errs = []
+		const results = JSON.parse(keysJson).map((k, i) => {
+			try { return [storage.getItem(k)] } catch (e) { errs.push([i, e]); return [null] }
+		})
+		return [JSON.stringify(results), errs]`)
+	setItems = js.Global().Get("Function").New(
+		"storage", "keysJson", "valuesJson",
+		`const values = JSON.parse(valuesJson)
+		const errs = []
+		JSON.parse(keysJson).forEach((k, i) => {
+			try { storage.setItem(k, values[i]) } catch (e) { errs.push([i, e]) }
+		})
+		return errs`)
+	return getItems, setItems, nil
+}
+
+// ItemResult is the result of getting a single key in GetItems.
+type ItemResult struct {
+	// Value is the value of the key, if it was found.
+	Value string
+
+	// Err is os.ErrNotExist if the key does not exist or the exception thrown
+	// by Javascript if the value could not be retrieved.
+	Err error
+}
+
+// GetItems returns the values from local storage for all the given key names
+// using a single call to Javascript. The result for each key is at the same
+// index as its key name. If the batch function cannot be created, each key is
+// retrieved with its own call instead.
+func (ls *LocalStorageJS) GetItems(keyNames []string) ([]ItemResult, error) {
+	getItemsJS, _, err := getBatchFuncs()
+	if err != nil {
+		return ls.getItemsEach(keyNames), nil
+	}
+
+	keysJson, err := json.Marshal(keyNames)
+	if err != nil {
+		return nil, err
+	}
+
+	resultsJS, err := exception.RunAndCatch(func() js.Value {
+		return getItemsJS.Invoke(ls.Value, string(keysJson))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results [][]*string
+	err = json.Unmarshal([]byte(resultsJS.Index(0).String()), &results)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal results")
+	}
+
+	items := make([]ItemResult, len(results))
+	for i, result := range results {
+		if len(result) == 0 || result[0] == nil {
+			items[i].Err = os.ErrNotExist
+		} else {
+			items[i].Value = *result[0]
+		}
+	}
+	for i, e := range keyExceptions(resultsJS.Index(1), len(items)) {
+		if e != nil {
+			items[i].Err = e
+		}
+	}
+
+	return items, nil
+}
+
+// getItemsEach returns the values from local storage for all the given key
+// names using a call to Javascript per key.
+func (ls *LocalStorageJS) getItemsEach(keyNames []string) []ItemResult {
+	items := make([]ItemResult, len(keyNames))
+	for i, keyName := range keyNames {
+		items[i].Value, items[i].Err = ls.GetItem(keyName)
+	}
+	return items
+}
+
+// SetItems adds the values to local storage at the given key names using a
+// single call to Javascript. Each value is set at the key name with the same
+// index. Returns the error for each key at the same index as its key name,
+// which is nil on success. If the batch function cannot be created, each key
+// is set with its own call instead.
+func (ls *LocalStorageJS) SetItems(keyNames, keyValues []string) ([]error, error) {
+	if len(keyNames) != len(keyValues) {
+		return nil, errors.Errorf("number of key names %d does not match "+
+			"number of values %d", len(keyNames), len(keyValues))
+	}
+
+	_, setItemsJS, err := getBatchFuncs()
+	if err != nil {
+		return ls.setItemsEach(keyNames, keyValues), nil
+	}
+
+	keysJson, err := json.Marshal(keyNames)
+	if err != nil {
+		return nil, err
+	}
+	valuesJson, err := json.Marshal(keyValues)
+	if err != nil {
+		return nil, err
+	}
+
+	resultsJS, err := exception.RunAndCatch(func() js.Value {
+		return setItemsJS.Invoke(ls.Value, string(keysJson), string(valuesJson))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keyExceptions(resultsJS, len(keyNames)), nil
+}
+
+// keyExceptions converts the [index, exception] pairs returned by the batch
+// functions to a list of n errors, where the error at each index is the
+// exception converted with exception.NewJSError or nil if there was none.
+func keyExceptions(pairs js.Value, n int) []error {
+	errs := make([]error, n)
+	for i := 0; i < pairs.Length(); i++ {
+		pair := pairs.Index(i)
+		if index := pair.Index(0).Int(); index >= 0 && index < n {
+			errs[index] = exception.NewJSError(pair.Index(1))
+		}
+	}
+	return errs
+}
+
+// setItemsEach adds the values to local storage at the given key names using a
+// call to Javascript per key.
+func (ls *LocalStorageJS) setItemsEach(keyNames, keyValues []string) []error {
+	errs := make([]error, len(keyNames))
+	for i, keyName := range keyNames {
+		errs[i] = ls.SetItem(keyName, keyValues[i])
+	}
+	return errs
+}
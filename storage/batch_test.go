@@ -0,0 +1,173 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"os"
+	"reflect"
+	"syscall/js"
+	"testing"
+
+	"gitlab.com/elixxir/wasm-utils/exception"
+)
+
+// Tests that values set with localStorage.SetMany and retrieved with
+// localStorage.GetMany match the originals.
+func TestLocalStorage_GetMany_SetMany(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	values := map[string][]byte{
+		"key1": []byte("key value"),
+		"key2": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		"key3": {0, 49, 0, 0, 0, 38, 249, 93},
+		"key4": {},
+	}
+
+	if err := jsStorage.(BatchStorage).SetMany(values); err != nil {
+		t.Fatalf("Failed to set values: %+v", err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for keyName := range values {
+		keys = append(keys, keyName)
+	}
+	loaded, err := jsStorage.(BatchStorage).GetMany(keys)
+	if err != nil {
+		t.Fatalf("Failed to get values: %+v", err)
+	}
+
+	for keyName, keyValue := range values {
+		if !bytes.Equal(keyValue, loaded[keyName]) {
+			t.Errorf("Loaded value does not match original for %q"+
+				"\nexpected: %q\nreceived: %q",
+				keyName, keyValue, loaded[keyName])
+		}
+
+		// Values set in a batch must be readable individually
+		if single, err := jsStorage.Get(keyName); err != nil {
+			t.Errorf("Failed to get %q: %+v", keyName, err)
+		} else if !bytes.Equal(keyValue, single) {
+			t.Errorf("Value from Get does not match original for %q"+
+				"\nexpected: %q\nreceived: %q", keyName, keyValue, single)
+		}
+	}
+}
+
+// Tests that localStorage.GetMany returns the values that exist and reports
+// os.ErrNotExist and ErrCorrupted for the keys that fail.
+func TestLocalStorage_GetMany_KeyErrors(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	if err := jsStorage.Set("exists", []byte("value")); err != nil {
+		t.Errorf("Failed to set: %+v", err)
+	}
+	err := jsStorage.LocalStorageUNSAFE().SetItem(
+		localStorageWasmPrefix+"corrupted", frameMarker+"abc")
+	if err != nil {
+		t.Errorf("Failed to set corrupted value: %+v", err)
+	}
+
+	loaded, err := jsStorage.(BatchStorage).GetMany(
+		[]string{"exists", "missing", "corrupted"})
+	expected := map[string][]byte{"exists": []byte("value")}
+	if !reflect.DeepEqual(expected, loaded) {
+		t.Errorf("Unexpected values.\nexpected: %q\nreceived: %q",
+			expected, loaded)
+	}
+
+	var keyErrs KeyErrors
+	if !errors.As(err, &keyErrs) {
+		t.Fatalf("Error is not KeyErrors: %+v", err)
+	} else if len(keyErrs) != 2 {
+		t.Errorf("Unexpected number of key errors.\nexpected: %d\nreceived: %d",
+			2, len(keyErrs))
+	}
+	if !errors.Is(keyErrs["missing"], os.ErrNotExist) {
+		t.Errorf("Incorrect error for missing key."+
+			"\nexpected: %v\nreceived: %v", os.ErrNotExist, keyErrs["missing"])
+	}
+	if !errors.Is(keyErrs["corrupted"], ErrCorrupted) {
+		t.Errorf("Incorrect error for corrupted key."+
+			"\nexpected: %v\nreceived: %v", ErrCorrupted, keyErrs["corrupted"])
+	}
+}
+
+// Tests that getItemsEach and setItemsEach, used when the batch functions
+// cannot be created, set and get each key.
+func TestLocalStorageJS_getItemsEach_setItemsEach(t *testing.T) {
+	ls := jsStorage.LocalStorageUNSAFE()
+	ls.Clear()
+
+	errs := ls.setItemsEach([]string{"key1", "key2"}, []string{"a", "b"})
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Failed to set key %d: %+v", i, err)
+		}
+	}
+
+	items := ls.getItemsEach([]string{"key1", "missing", "key2"})
+	if items[0].Value != "a" || items[0].Err != nil {
+		t.Errorf("Unexpected result for key1: %+v", items[0])
+	}
+	if !errors.Is(items[1].Err, os.ErrNotExist) {
+		t.Errorf("Incorrect error for missing key."+
+			"\nexpected: %v\nreceived: %v", os.ErrNotExist, items[1].Err)
+	}
+	if items[2].Value != "b" || items[2].Err != nil {
+		t.Errorf("Unexpected result for key2: %+v", items[2])
+	}
+}
+
+// Tests that the exceptions thrown for individual keys by GetItems and
+// SetItems are returned as *exception.JSError with the name of the Javascript
+// exception, the same as when each key is set individually.
+func TestLocalStorageJS_GetItems_SetItems_Exceptions(t *testing.T) {
+	mock := js.Global().Get("Function").New(`
+		const quotaError = () => new DOMException("quota", "QuotaExceededError")
+		class MockStorage {
+			map = new Map()
+			getItem(k) { if (k === "bad") throw new TypeError("bad key"); return this.map.get(k) ?? null }
+			setItem(k, v) { if (k === "bad") throw quotaError(); this.map.set(k, String(v)) }
+		}
+		return new MockStorage()`).Invoke()
+	ls := &LocalStorageJS{mock}
+
+	errs, err := ls.SetItems([]string{"good", "bad"}, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Failed to set items: %+v", err)
+	}
+	if errs[0] != nil {
+		t.Errorf("Failed to set good key: %+v", errs[0])
+	}
+	var jsErr *exception.JSError
+	if !errors.As(errs[1], &jsErr) {
+		t.Errorf("Error for bad key is not a JSError: %+v", errs[1])
+	} else if !isQuotaError(errs[1]) {
+		t.Errorf("Error for bad key is not a quota error.\nexpected: %s"+
+			"\nreceived: %s", exception.QuotaExceededErrorName, jsErr.Name)
+	}
+
+	items, err := ls.GetItems([]string{"good", "missing", "bad"})
+	if err != nil {
+		t.Fatalf("Failed to get items: %+v", err)
+	}
+	if items[0].Value != "a" || items[0].Err != nil {
+		t.Errorf("Unexpected result for good key: %+v", items[0])
+	}
+	if !errors.Is(items[1].Err, os.ErrNotExist) {
+		t.Errorf("Incorrect error for missing key."+
+			"\nexpected: %v\nreceived: %v", os.ErrNotExist, items[1].Err)
+	}
+	if !errors.As(items[2].Err, &jsErr) || jsErr.Name != "TypeError" ||
+		jsErr.Message != "bad key" {
+		t.Errorf("Incorrect error for bad key.\nexpected: %s\nreceived: %+v",
+			"TypeError: bad key", items[2].Err)
+	}
+}
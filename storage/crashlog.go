@@ -0,0 +1,191 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	jww "github.com/spf13/jwalterweatherman"
+
+	"gitlab.com/elixxir/wasm-utils/exception"
+)
+
+// DefaultCrashLogSize is the number of entries kept by a CrashLog when no size
+// is specified.
+const DefaultCrashLogSize = 16
+
+// CrashEntry is a single error recorded in a CrashLog.
+type CrashEntry struct {
+	// Time is when the error was reported.
+	Time time.Time `json:"time"`
+
+	// Message is the error message.
+	Message string `json:"message"`
+
+	// Trace is the error formatted with %+v, which includes its stack trace
+	// if it has one.
+	Trace string `json:"trace"`
+
+	// Kind describes how the error occurred (e.g., "panic" or "throw").
+	// Optional.
+	Kind string `json:"kind,omitempty"`
+
+	// Goroutines is the stack dump of all goroutines when the error was
+	// recorded. Optional.
+	Goroutines string `json:"goroutines,omitempty"`
+
+	// Build is the build information of the binary. Optional.
+	Build string `json:"build,omitempty"`
+
+	// Logs are the most recent log lines before the error. Optional.
+	Logs []string `json:"logs,omitempty"`
+}
+
+// CrashLog persists reported errors to a LocalStorage so that they survive the
+// WASM module terminating. Its Report method can be registered with
+// exception.SetReporter to record recovered panics.
+//
+// The log keeps only the most recent entries, up to its size. If the storage
+// quota is reached, the oldest entries are removed until the log fits.
+//
+// The log is read from storage on every Add so that entries saved by other
+// tabs sharing the storage are kept and a removed log is not restored.
+type CrashLog struct {
+	storage LocalStorage
+	key     string
+	size    int
+	mux     sync.Mutex
+}
+
+// NewCrashLog returns a CrashLog that saves its entries to the storage at the
+// given key name and keeps at most size entries. If size is 0 or less,
+// DefaultCrashLogSize is used.
+func NewCrashLog(storage LocalStorage, key string, size int) *CrashLog {
+	if size <= 0 {
+		size = DefaultCrashLogSize
+	}
+	return &CrashLog{storage: storage, key: key, size: size}
+}
+
+// Report saves the error to the log. Errors saving the log are printed to the
+// log instead of being returned so that Report can be used as an
+// exception.Reporter.
+func (cl *CrashLog) Report(err error) {
+	entry := CrashEntry{
+		Time:    time.Now(),
+		Message: err.Error(),
+		Trace:   fmt.Sprintf("%+v", err),
+	}
+	if saveErr := cl.Add(entry); saveErr != nil {
+		jww.ERROR.Printf("Failed to save error to crash log: %+v", saveErr)
+	}
+}
+
+// Add saves the entry to the log and removes the oldest entries that exceed
+// the log's size. If the storage quota is reached, the oldest entries are
+// removed until the log can be saved. Returns an error if the entry cannot be
+// saved on its own.
+func (cl *CrashLog) Add(entry CrashEntry) error {
+	cl.mux.Lock()
+	defer cl.mux.Unlock()
+
+	entries, err := cl.load()
+	if err != nil {
+		// Start a new log rather than lose the new entry
+		jww.WARN.Printf("Replacing unreadable crash log: %+v", err)
+		entries = nil
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > cl.size {
+		entries = entries[len(entries)-cl.size:]
+	}
+
+	for {
+		data, err := json.Marshal(entries)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal crash log")
+		}
+
+		err = cl.storage.Set(cl.key, data)
+		if err == nil {
+			return nil
+		} else if !isQuotaError(err) || len(entries) == 1 {
+			return errors.Wrap(err, "failed to save crash log")
+		}
+
+		jww.WARN.Printf("Storage quota reached; removing oldest crash log "+
+			"entry from %s", entries[0].Time)
+		entries = entries[1:]
+	}
+}
+
+// Entries returns all entries in the log, from oldest to newest.
+func (cl *CrashLog) Entries() ([]CrashEntry, error) {
+	cl.mux.Lock()
+	defer cl.mux.Unlock()
+	entries, err := cl.load()
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Clear removes all entries from the log.
+func (cl *CrashLog) Clear() {
+	cl.mux.Lock()
+	defer cl.mux.Unlock()
+	cl.storage.RemoveItem(cl.key)
+}
+
+// load reads the entries from storage. Returns no entries if the log has not
+// been saved.
+func (cl *CrashLog) load() ([]CrashEntry, error) {
+	data, err := cl.storage.Get(cl.key)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to load crash log")
+	}
+
+	var entries []CrashEntry
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal crash log")
+	}
+	return entries, nil
+}
+
+// quotaErrorNames are the names of the Javascript errors thrown by browsers
+// when the storage quota is reached.
+var quotaErrorNames = []string{
+	exception.QuotaExceededErrorName,
+	"NS_ERROR_DOM_QUOTA_REACHED", // Firefox
+}
+
+// isQuotaError returns true if the error was thrown because the storage quota
+// was reached.
+func isQuotaError(err error) bool {
+	var jsErr *exception.JSError
+	if !errors.As(err, &jsErr) {
+		return false
+	}
+	for _, name := range quotaErrorNames {
+		if jsErr.Name == name {
+			return true
+		}
+	}
+	return false
+}
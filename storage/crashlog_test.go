@@ -0,0 +1,138 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"strconv"
+	"strings"
+	"syscall/js"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Tests that errors reported to a CrashLog are persisted, that only the most
+// recent entries are kept, and that CrashLog.Clear removes them.
+func TestCrashLog(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	cl := NewCrashLog(jsStorage, "crashLog", 3)
+
+	for i := 0; i < 5; i++ {
+		cl.Report(errors.New("error " + strconv.Itoa(i)))
+	}
+
+	entries, err := NewCrashLog(jsStorage, "crashLog", 3).Entries()
+	if err != nil {
+		t.Fatalf("Failed to get entries: %+v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Incorrect number of entries.\nexpected: %d\nreceived: %d",
+			3, len(entries))
+	}
+	for i, entry := range entries {
+		expected := "error " + strconv.Itoa(i+2)
+		if entry.Message != expected {
+			t.Errorf("Incorrect message for entry %d.\nexpected: %s\nreceived: %s",
+				i, expected, entry.Message)
+		}
+		if !strings.Contains(entry.Trace, "TestCrashLog") {
+			t.Errorf("Trace of entry %d missing stack trace:\n%s", i, entry.Trace)
+		}
+		if entry.Time.IsZero() {
+			t.Errorf("Entry %d has no time.", i)
+		}
+	}
+
+	cl.Clear()
+	if entries, err = cl.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("Entries not cleared: %v (%+v)", entries, err)
+	}
+}
+
+// Tests that CrashLog.Add keeps entries saved by another CrashLog using the
+// same key, such as one in another tab, and does not restore entries after the
+// log is removed from storage.
+func TestCrashLog_Add_Shared(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	cl1 := NewCrashLog(jsStorage, "crashLog", 5)
+	cl2 := NewCrashLog(jsStorage, "crashLog", 5)
+
+	cl1.Report(errors.New("error 0"))
+	cl2.Report(errors.New("error 1"))
+	cl1.Report(errors.New("error 2"))
+
+	entries, err := cl2.Entries()
+	if err != nil {
+		t.Fatalf("Failed to get entries: %+v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Incorrect number of entries.\nexpected: %d\nreceived: %d",
+			3, len(entries))
+	}
+	for i, entry := range entries {
+		if expected := "error " + strconv.Itoa(i); entry.Message != expected {
+			t.Errorf("Incorrect message for entry %d.\nexpected: %s\nreceived: %s",
+				i, expected, entry.Message)
+		}
+	}
+
+	jsStorage.RemoveItem("crashLog")
+	cl1.Report(errors.New("error 3"))
+	if entries, err = cl2.Entries(); err != nil {
+		t.Fatalf("Failed to get entries: %+v", err)
+	} else if len(entries) != 1 || entries[0].Message != "error 3" {
+		t.Errorf("Removed entries restored: %v", entries)
+	}
+}
+
+// Tests that when the storage quota is reached, CrashLog.Add removes the
+// oldest entries until the log fits and returns an error only if the new entry
+// does not fit on its own.
+func TestCrashLog_Add_Quota(t *testing.T) {
+	// Storage that throws a QuotaExceededError for values over 100 characters
+	quotaStorage := js.Global().Get("Function").New("limit", `
+		const s = Object.create({
+			getItem(k) { return Object.hasOwn(this, k) ? this[k] : null; },
+			setItem(k, v) {
+				if (String(v).length > limit) {
+					const err = new Error("quota exceeded");
+					err.name = "QuotaExceededError";
+					throw err;
+				}
+				this[k] = String(v);
+			},
+			removeItem(k) { delete this[k]; },
+		});
+		return s;`).Invoke(100)
+	cl := NewCrashLog(NewStorage(quotaStorage, ""), "crashLog", 10)
+
+	for i := 0; i < 5; i++ {
+		err := cl.Add(CrashEntry{Message: "error " + strconv.Itoa(i)})
+		if err != nil {
+			t.Fatalf("Failed to add entry %d: %+v", i, err)
+		}
+	}
+
+	entries, err := NewCrashLog(NewStorage(quotaStorage, ""), "crashLog", 10).
+		Entries()
+	if err != nil {
+		t.Fatalf("Failed to get entries: %+v", err)
+	} else if len(entries) == 0 || len(entries) == 5 {
+		t.Fatalf("Oldest entries not removed: %d entries", len(entries))
+	} else if last := entries[len(entries)-1].Message; last != "error 4" {
+		t.Errorf("Newest entry not kept.\nexpected: %s\nreceived: %s",
+			"error 4", last)
+	}
+
+	err = cl.Add(CrashEntry{Message: strings.Repeat("x", 400)})
+	if err == nil || !isQuotaError(err) {
+		t.Errorf("Expected quota error for oversized entry: %+v", err)
+	}
+}
@@ -0,0 +1,110 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/Max-Sum/base32768"
+	"github.com/pkg/errors"
+)
+
+// Values saved by localStorage are framed so that corruption can be detected
+// when they are read back. The frame is encoded to a string and prefixed with
+// frameMarker, which is not part of the base32768 alphabet, to distinguish it
+// from legacy values that were saved as the plain base32768 encoding of the
+// value.
+//
+//	+---------+-----------------+-------+
+//	| version | CRC-32 checksum | value |
+//	| 1 byte  |     4 bytes     |  ...  |
+//	+---------+-----------------+-------+
+const (
+	frameMarker  = "§"
+	frameVersion = 1
+
+	frameVersionLen  = 1
+	frameChecksumLen = 4
+	frameHeaderLen   = frameVersionLen + frameChecksumLen
+)
+
+// ErrCorrupted is returned when a stored value fails its integrity check or
+// cannot be decoded.
+var ErrCorrupted = errors.New("stored value is corrupted")
+
+// CorruptedError describes a corrupted value in storage. It matches
+// ErrCorrupted when using errors.Is.
+type CorruptedError struct {
+	// Key is the name of the key whose value is corrupted.
+	Key string
+
+	// Err is the reason the value was deemed corrupted.
+	Err error
+}
+
+// Error returns the key name and the reason the value is corrupted.
+func (e *CorruptedError) Error() string {
+	return fmt.Sprintf("value of key %q is corrupted: %v", e.Key, e.Err)
+}
+
+// Is returns true if target is ErrCorrupted.
+func (e *CorruptedError) Is(target error) bool { return target == ErrCorrupted }
+
+// Unwrap returns the reason the value is corrupted.
+func (e *CorruptedError) Unwrap() error { return e.Err }
+
+// encodeValue frames the value with its version and checksum and encodes it to
+// a string that can be saved to storage.
+func encodeValue(value []byte) string {
+	frame := make([]byte, frameHeaderLen+len(value))
+	frame[0] = frameVersion
+	binary.BigEndian.PutUint32(frame[frameVersionLen:], crc32.ChecksumIEEE(value))
+	copy(frame[frameHeaderLen:], value)
+	return frameMarker + base32768.SafeEncoding.EncodeToString(frame)
+}
+
+// decodeValue decodes the value read from storage and verifies its checksum.
+// Values saved before framing was introduced are decoded without a check.
+// Returns a CorruptedError if the value cannot be decoded or does not match its
+// checksum.
+func decodeValue(keyName, encoded string) ([]byte, error) {
+	if !strings.HasPrefix(encoded, frameMarker) {
+		value, err := base32768.SafeEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, &CorruptedError{keyName, err}
+		}
+		return value, nil
+	}
+
+	frame, err := base32768.SafeEncoding.DecodeString(
+		strings.TrimPrefix(encoded, frameMarker))
+	if err != nil {
+		return nil, &CorruptedError{keyName, err}
+	} else if len(frame) < frameHeaderLen {
+		return nil, &CorruptedError{keyName, errors.Errorf(
+			"frame of %d bytes is shorter than the %d byte header",
+			len(frame), frameHeaderLen)}
+	} else if frame[0] != frameVersion {
+		return nil, &CorruptedError{keyName,
+			errors.Errorf("unknown frame version %d", frame[0])}
+	}
+
+	value := frame[frameHeaderLen:]
+	expected := binary.BigEndian.Uint32(frame[frameVersionLen:])
+	if checksum := crc32.ChecksumIEEE(value); checksum != expected {
+		return nil, &CorruptedError{keyName, errors.Errorf(
+			"checksum %08x does not match expected %08x", checksum, expected)}
+	}
+
+	return value, nil
+}
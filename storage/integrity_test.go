@@ -0,0 +1,107 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"bytes"
+	"github.com/Max-Sum/base32768"
+	"github.com/pkg/errors"
+	"strings"
+	"testing"
+)
+
+// Tests that a value encoded with encodeValue and decoded with decodeValue
+// matches the original.
+func Test_encodeValue_decodeValue(t *testing.T) {
+	values := [][]byte{
+		{},
+		[]byte("key value"),
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{0, 49, 0, 0, 0, 38, 249, 93, 242, 189, 222, 32, 138, 248, 121},
+	}
+
+	for i, value := range values {
+		decoded, err := decodeValue("key", encodeValue(value))
+		if err != nil {
+			t.Errorf("Failed to decode value #%d: %+v", i, err)
+		} else if !bytes.Equal(value, decoded) {
+			t.Errorf("Decoded value #%d does not match original."+
+				"\nexpected: %q\nreceived: %q", i, value, decoded)
+		}
+	}
+}
+
+// Tests that decodeValue can decode a legacy value that was saved without a
+// frame.
+func Test_decodeValue_Legacy(t *testing.T) {
+	value := []byte("legacy value")
+	decoded, err := decodeValue("key", base32768.SafeEncoding.EncodeToString(value))
+	if err != nil {
+		t.Errorf("Failed to decode legacy value: %+v", err)
+	} else if !bytes.Equal(value, decoded) {
+		t.Errorf("Decoded value does not match original."+
+			"\nexpected: %q\nreceived: %q", value, decoded)
+	}
+}
+
+// Tests that localStorage.Get returns ErrCorrupted when the frame of the value
+// is modified.
+func TestLocalStorage_Get_CorruptedError(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	keyName := "key"
+	if err := jsStorage.Set(keyName, []byte("some value")); err != nil {
+		t.Errorf("Failed to set %q: %+v", keyName, err)
+	}
+
+	// Replace the value with a frame whose value no longer matches its
+	// checksum
+	frame, _ := base32768.SafeEncoding.DecodeString(
+		strings.TrimPrefix(encodeValue([]byte("some value")), frameMarker))
+	frame[frameHeaderLen] ^= 0xFF
+	err := jsStorage.LocalStorageUNSAFE().SetItem(localStorageWasmPrefix+keyName,
+		frameMarker+base32768.SafeEncoding.EncodeToString(frame))
+	if err != nil {
+		t.Errorf("Failed to set corrupted %q: %+v", keyName, err)
+	}
+
+	_, err = jsStorage.Get(keyName)
+	var ce *CorruptedError
+	if !errors.Is(err, ErrCorrupted) || !errors.As(err, &ce) || ce.Key != keyName {
+		t.Errorf("Incorrect error for corrupted value."+
+			"\nexpected: %v\nreceived: %v", ErrCorrupted, err)
+	}
+}
+
+// Tests that localStorage.Verify returns only the keys with corrupted values,
+// including legacy values that cannot be decoded.
+func TestLocalStorage_Verify(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	for _, keyName := range []string{"a", "b", "c", "d"} {
+		if err := jsStorage.Set(keyName, []byte(keyName)); err != nil {
+			t.Errorf("Failed to set %q: %+v", keyName, err)
+		}
+	}
+
+	unsafe := jsStorage.LocalStorageUNSAFE()
+	err := unsafe.SetItem(localStorageWasmPrefix+"b", frameMarker+"abc")
+	if err != nil {
+		t.Errorf("Failed to set corrupted %q: %+v", "b", err)
+	}
+	err = unsafe.SetItem(localStorageWasmPrefix+"d", "not base32768")
+	if err != nil {
+		t.Errorf("Failed to set corrupted %q: %+v", "d", err)
+	}
+
+	corrupted := jsStorage.(Verifier).Verify()
+	if len(corrupted) != 2 || corrupted[0].Key != "b" || corrupted[1].Key != "d" {
+		t.Errorf("Unexpected corrupted keys.\nexpected: %q\nreceived: %v",
+			[]string{"b", "d"}, corrupted)
+	}
+}
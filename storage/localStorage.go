@@ -11,13 +11,13 @@ package storage
 
 import (
 	"os"
+	"sort"
 	"strings"
 	"syscall/js"
 
-	"github.com/Max-Sum/base32768"
+	"github.com/pkg/errors"
 
 	"gitlab.com/elixxir/wasm-utils/exception"
-	"gitlab.com/elixxir/wasm-utils/utils"
 )
 
 // localStorageWasmPrefix is prefixed to every keyName saved to local storage by
@@ -33,7 +33,8 @@ const localStorageWasmPrefix = "🞮🞮"
 // specifically for web-based implementations.
 type LocalStorage interface {
 	// Get decodes and returns the value from the local storage given its key
-	// name. Returns os.ErrNotExist if the key does not exist.
+	// name. Returns os.ErrNotExist if the key does not exist and a
+	// CorruptedError if the value fails its integrity check.
 	Get(key string) ([]byte, error)
 
 	// Set encodes the bytes to a string and adds them to local storage at the
@@ -73,7 +74,34 @@ type LocalStorage interface {
 	LocalStorageUNSAFE() *LocalStorageJS
 }
 
-// localStorage contains the js.Value representation of localStorage.
+// BatchStorage is implemented by storage that can get and set multiple keys at
+// once, such as the LocalStorage returned by GetLocalStorage and
+// GetSessionStorage. It is separate from LocalStorage so that other
+// implementations of LocalStorage are not required to implement it.
+type BatchStorage interface {
+	// GetMany decodes and returns the values of all the given keys using a
+	// single call to Javascript. If any key fails, the values that succeeded
+	// are returned with a KeyErrors containing the error for each failed key.
+	GetMany(keys []string) (map[string][]byte, error)
+
+	// SetMany encodes all the values and adds them to local storage using a
+	// single call to Javascript. If any key fails, the remaining keys are still
+	// set and a KeyErrors is returned containing the error for each failed key.
+	SetMany(values map[string][]byte) error
+}
+
+// Verifier is implemented by storage that can check the integrity of its
+// values, such as the LocalStorage returned by GetLocalStorage and
+// GetSessionStorage. It is separate from LocalStorage so that other
+// implementations of LocalStorage are not required to implement it.
+type Verifier interface {
+	// Verify checks the integrity of every value in storage and returns an
+	// error for each corrupted value, sorted by key name.
+	Verify() []*CorruptedError
+}
+
+// localStorage contains the js.Value representation of localStorage or any
+// other object implementing the Storage Web API.
 type localStorage struct {
 	// The Javascript value containing the localStorage object
 	v *LocalStorageJS
@@ -89,10 +117,23 @@ type localStorage struct {
 // Doc: https://developer.mozilla.org/en-US/docs/Web/API/Window/localStorage
 var jsStorage LocalStorage = newLocalStorage(localStorageWasmPrefix)
 
+// jsSessionStorage is the global that stores Javascript as
+// window.sessionStorage.
+//
+// Doc: https://developer.mozilla.org/en-US/docs/Web/API/Window/sessionStorage
+var jsSessionStorage LocalStorage = newStorage(
+	js.Global().Get("sessionStorage"), localStorageWasmPrefix)
+
 // newLocalStorage creates a new localStorage object with the specified prefix.
 func newLocalStorage(prefix string) *localStorage {
+	return newStorage(js.Global().Get("localStorage"), prefix)
+}
+
+// newStorage creates a new localStorage object that wraps the Javascript
+// Storage object with the specified prefix.
+func newStorage(storage js.Value, prefix string) *localStorage {
 	return &localStorage{
-		v:      &LocalStorageJS{js.Global().Get("localStorage")},
+		v:      &LocalStorageJS{storage},
 		prefix: prefix,
 	}
 }
@@ -102,22 +143,39 @@ func GetLocalStorage() LocalStorage {
 	return jsStorage
 }
 
+// GetSessionStorage returns Javascript's session storage. Values in session
+// storage are cleared when the page session ends (i.e. when the tab is closed).
+func GetSessionStorage() LocalStorage {
+	return jsSessionStorage
+}
+
+// NewStorage returns a LocalStorage backed by the given Javascript object,
+// which must implement the Storage Web API. Each key name is prefixed with the
+// given prefix so that only keys created by it are managed. This is primarily
+// used for testing with a mock Storage object.
+//
+// Doc: https://developer.mozilla.org/en-US/docs/Web/API/Storage
+func NewStorage(storage js.Value, prefix string) LocalStorage {
+	return newStorage(storage, prefix)
+}
+
 // Get decodes and returns the value from the local storage given its key
-// name. Returns os.ErrNotExist if the key does not exist.
+// name. Returns os.ErrNotExist if the key does not exist and a CorruptedError
+// if the value fails its integrity check.
 func (ls *localStorage) Get(keyName string) ([]byte, error) {
 	value, err := ls.v.GetItem(ls.prefix + keyName)
 	if err != nil {
 		return nil, err
 	}
 
-	return base32768.SafeEncoding.DecodeString(value)
+	return decodeValue(keyName, value)
 }
 
-// Set encodes the bytes to a string and adds them to local storage at the
-// given key name. Returns an error if local storage quota has been reached.
+// Set encodes the bytes to a string, framed with a checksum, and adds them to
+// local storage at the given key name. Returns an error if local storage quota
+// has been reached.
 func (ls *localStorage) Set(keyName string, keyValue []byte) error {
-	encoded := base32768.SafeEncoding.EncodeToString(keyValue)
-	return ls.v.SetItem(ls.prefix+keyName, encoded)
+	return ls.v.SetItem(ls.prefix+keyName, encodeValue(keyValue))
 }
 
 // RemoveItem removes a key's value from local storage given its name. If there
@@ -173,6 +231,24 @@ func (ls *localStorage) Length() int {
 	return ls.v.Length()
 }
 
+// Verify checks the integrity of every value in storage and returns an error
+// for each corrupted value, sorted by key name.
+func (ls *localStorage) Verify() []*CorruptedError {
+	keys := ls.v.KeysPrefix(ls.prefix)
+	sort.Strings(keys)
+
+	var corrupted []*CorruptedError
+	for _, keyName := range keys {
+		_, err := ls.Get(keyName)
+		var ce *CorruptedError
+		if errors.As(err, &ce) {
+			corrupted = append(corrupted, ce)
+		}
+	}
+
+	return corrupted
+}
+
 // LocalStorageUNSAFE returns the underlying local storage wrapper. This can be
 // UNSAFE and should only be used if you know what you are doing.
 //
@@ -189,10 +265,11 @@ func (ls *localStorage) LocalStorageUNSAFE() *LocalStorageJS {
 // Javascript Wrappers                                                        //
 ////////////////////////////////////////////////////////////////////////////////
 
-// LocalStorageJS stores the Javascript window.localStorage object and wraps all
-// of its methods and fields to handle type conversations and errors.
+// LocalStorageJS stores a Javascript Storage object, such as
+// window.localStorage or window.sessionStorage, and wraps all of its methods
+// and fields to handle type conversations and errors.
 //
-// Doc: https://developer.mozilla.org/en-US/docs/Web/API/Window/localStorage
+// Doc: https://developer.mozilla.org/en-US/docs/Web/API/Storage
 type LocalStorageJS struct {
 	js.Value
 }
@@ -248,12 +325,16 @@ func (ls *LocalStorageJS) Key(n int) (keyName string, err error) {
 	return keyNameJS.String(), nil
 }
 
-// Keys returns a list of all key names in local storage.
+// Keys returns a list of all key names in local storage. The keys are listed
+// with the Storage API (length and key) so that any Storage-like object is
+// supported.
 func (ls *LocalStorageJS) Keys() []string {
-	keysJS := utils.Object.Call("keys", ls.Value)
-	keys := make([]string, keysJS.Length())
-	for i := range keys {
-		keys[i] = keysJS.Index(i).String()
+	n := ls.Length()
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if keyName, err := ls.Key(i); err == nil {
+			keys = append(keys, keyName)
+		}
 	}
 	return keys
 }
@@ -261,10 +342,8 @@ func (ls *LocalStorageJS) Keys() []string {
 // KeysPrefix returns a list of all key names in local storage with the given
 // prefix and trims the prefix from each key name.
 func (ls *LocalStorageJS) KeysPrefix(prefix string) []string {
-	keysJS := utils.Object.Call("keys", ls.Value)
-	keys := make([]string, 0, keysJS.Length())
-	for i := 0; i < keysJS.Length(); i++ {
-		keyName := keysJS.Index(i).String()
+	var keys []string
+	for _, keyName := range ls.Keys() {
 		if strings.HasPrefix(keyName, prefix) {
 			keys = append(keys, strings.TrimPrefix(keyName, prefix))
 		}
@@ -0,0 +1,136 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrVerificationFailed is returned by Migrate when a value read back from the
+// destination does not match the value in the source.
+var ErrVerificationFailed = errors.New("migrated value does not match source")
+
+// MigrateOptions controls how Migrate copies keys between two LocalStorage
+// implementations.
+type MigrateOptions struct {
+	// Namespace is the key name prefix of the keys to migrate. If empty, every
+	// key in the source is migrated.
+	Namespace string
+
+	// Progress, if set, is called after each key is migrated with the number
+	// of keys migrated so far, the total number of keys being migrated, and
+	// the name of the key that was just migrated.
+	Progress func(migrated, total int, keyName string)
+
+	// Verify re-reads every value from the destination after it is written and
+	// compares it to the source value. On mismatch, Migrate stops and returns
+	// ErrVerificationFailed.
+	Verify bool
+
+	// CheckpointKey is the key name in the destination where the name of the
+	// last migrated key is saved. If a checkpoint exists when Migrate is
+	// called, all keys up to and including the checkpoint are skipped so that
+	// an interrupted migration can be resumed. The checkpoint is removed once
+	// the migration completes. If empty, no checkpoint is kept.
+	CheckpointKey string
+
+	// DeleteSource removes each key from the source once it has been copied
+	// and verified. It requires Verify to be set.
+	DeleteSource bool
+}
+
+// Migrate copies all keys in the namespace from src to dst and returns the
+// number of keys migrated. Keys are migrated in sorted order so that the
+// migration can be resumed from a checkpoint.
+func Migrate(src, dst LocalStorage, opts MigrateOptions) (int, error) {
+	if opts.DeleteSource && !opts.Verify {
+		return 0, errors.New(
+			"cannot delete source keys without verifying the migration")
+	}
+
+	keys := make([]string, 0, src.Length())
+	for _, keyName := range src.Keys() {
+		if strings.HasPrefix(keyName, opts.Namespace) &&
+			keyName != opts.CheckpointKey {
+			keys = append(keys, keyName)
+		}
+	}
+	sort.Strings(keys)
+
+	// Skip all keys already migrated in a previous run
+	if opts.CheckpointKey != "" {
+		checkpoint, err := dst.Get(opts.CheckpointKey)
+		if err == nil {
+			keys = keys[sort.SearchStrings(keys, string(checkpoint)+"\x00"):]
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return 0, errors.Wrapf(err, "failed to load checkpoint %q",
+				opts.CheckpointKey)
+		}
+	}
+
+	for i, keyName := range keys {
+		if err := migrateKey(src, dst, keyName, opts); err != nil {
+			return i, err
+		}
+
+		if opts.CheckpointKey != "" {
+			err := dst.Set(opts.CheckpointKey, []byte(keyName))
+			if err != nil {
+				return i + 1, errors.Wrapf(err,
+					"failed to save checkpoint after key %q", keyName)
+			}
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(keys), keyName)
+		}
+	}
+
+	if opts.CheckpointKey != "" {
+		dst.RemoveItem(opts.CheckpointKey)
+	}
+
+	return len(keys), nil
+}
+
+// migrateKey copies a single key from src to dst, verifying and deleting the
+// source according to the options.
+func migrateKey(
+	src, dst LocalStorage, keyName string, opts MigrateOptions) error {
+	value, err := src.Get(keyName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %q from source", keyName)
+	}
+
+	if err = dst.Set(keyName, value); err != nil {
+		return errors.Wrapf(err, "failed to set %q in destination", keyName)
+	}
+
+	if opts.Verify {
+		copied, err := dst.Get(keyName)
+		if err != nil {
+			return errors.Wrapf(err,
+				"failed to get %q from destination for verification", keyName)
+		} else if !bytes.Equal(value, copied) {
+			return errors.Wrapf(ErrVerificationFailed, "key %q", keyName)
+		}
+	}
+
+	if opts.DeleteSource {
+		src.RemoveItem(keyName)
+	}
+
+	return nil
+}
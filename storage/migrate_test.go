@@ -0,0 +1,134 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// Tests that Migrate copies all keys in the namespace to the destination,
+// reports progress for each key, and leaves other keys untouched.
+func TestMigrate(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	src, dst := newLocalStorage("src/"), newLocalStorage("dst/")
+	values := map[string][]byte{
+		"ns/key1": []byte("key value"),
+		"ns/key2": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		"ns/key3": {0, 49, 0, 0, 0, 38, 249, 93},
+	}
+	for keyName, keyValue := range values {
+		if err := src.Set(keyName, keyValue); err != nil {
+			t.Errorf("Failed to set %q: %+v", keyName, err)
+		}
+	}
+	if err := src.Set("other", []byte("other")); err != nil {
+		t.Errorf("Failed to set %q: %+v", "other", err)
+	}
+
+	var calls int
+	n, err := Migrate(src, dst, MigrateOptions{
+		Namespace: "ns/",
+		Progress: func(migrated, total int, keyName string) {
+			calls++
+			if migrated != calls || total != len(values) {
+				t.Errorf("Unexpected progress for %q.\nexpected: %d/%d"+
+					"\nreceived: %d/%d", keyName, calls, len(values),
+					migrated, total)
+			}
+		},
+		Verify: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to migrate: %+v", err)
+	} else if n != len(values) {
+		t.Errorf("Incorrect number of keys migrated."+
+			"\nexpected: %d\nreceived: %d", len(values), n)
+	}
+
+	for keyName, keyValue := range values {
+		copied, err := dst.Get(keyName)
+		if err != nil {
+			t.Errorf("Failed to get %q from destination: %+v", keyName, err)
+		} else if !bytes.Equal(keyValue, copied) {
+			t.Errorf("Copied value does not match original for %q"+
+				"\nexpected: %q\nreceived: %q", keyName, keyValue, copied)
+		}
+		if _, err = src.Get(keyName); err != nil {
+			t.Errorf("Source %q was modified: %+v", keyName, err)
+		}
+	}
+
+	if _, err = dst.Get("other"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Key outside of namespace was migrated: %+v", err)
+	}
+}
+
+// Tests that Migrate resumes after the key saved in the checkpoint, deletes the
+// source keys, and removes the checkpoint once done.
+func TestMigrate_CheckpointDeleteSource(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	src, dst := newLocalStorage("src/"), newLocalStorage("dst/")
+	const numKeys = 6
+	for i := 0; i < numKeys; i++ {
+		keyName := "key" + strconv.Itoa(i)
+		if err := src.Set(keyName, []byte(strconv.Itoa(i))); err != nil {
+			t.Errorf("Failed to set %q: %+v", keyName, err)
+		}
+	}
+
+	const checkpointKey = "migrateCheckpoint"
+	if err := dst.Set(checkpointKey, []byte("key2")); err != nil {
+		t.Errorf("Failed to set checkpoint: %+v", err)
+	}
+
+	n, err := Migrate(src, dst, MigrateOptions{
+		Verify:        true,
+		CheckpointKey: checkpointKey,
+		DeleteSource:  true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to migrate: %+v", err)
+	} else if n != numKeys-3 {
+		t.Errorf("Incorrect number of keys migrated."+
+			"\nexpected: %d\nreceived: %d", numKeys-3, n)
+	}
+
+	for i := 0; i < numKeys; i++ {
+		keyName := "key" + strconv.Itoa(i)
+		_, dstErr := dst.Get(keyName)
+		_, srcErr := src.Get(keyName)
+		if i <= 2 {
+			if !errors.Is(dstErr, os.ErrNotExist) || srcErr != nil {
+				t.Errorf("Key %q before checkpoint was migrated.", keyName)
+			}
+		} else if dstErr != nil || !errors.Is(srcErr, os.ErrNotExist) {
+			t.Errorf("Key %q after checkpoint was not migrated."+
+				"\ndestination: %v\nsource: %v", keyName, dstErr, srcErr)
+		}
+	}
+
+	if _, err = dst.Get(checkpointKey); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Checkpoint was not removed: %+v", err)
+	}
+}
+
+// Tests that Migrate refuses to delete source keys when
+// verification is disabled.
+func TestMigrate_DeleteSourceNoVerifyError(t *testing.T) {
+	_, err := Migrate(jsStorage, newLocalStorage("dst/"),
+		MigrateOptions{DeleteSource: true})
+	if err == nil {
+		t.Errorf("Migrate did not fail when deleting without verification.")
+	}
+}
@@ -0,0 +1,99 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"os"
+	"reflect"
+	"syscall/js"
+	"testing"
+)
+
+// Unit test of GetSessionStorage.
+func TestGetSessionStorage(t *testing.T) {
+	expected := &localStorage{
+		v:      &LocalStorageJS{js.Global().Get("sessionStorage")},
+		prefix: localStorageWasmPrefix,
+	}
+
+	ss := GetSessionStorage()
+
+	if !reflect.DeepEqual(expected, ss) {
+		t.Errorf("Did not receive expected sessionStorage."+
+			"\nexpected: %+v\nreceived: %+v", expected, ss)
+	}
+}
+
+// Tests that values set in session storage are not visible in local storage.
+func TestGetSessionStorage_Separate(t *testing.T) {
+	jsStorage.LocalStorageUNSAFE().Clear()
+	jsSessionStorage.LocalStorageUNSAFE().Clear()
+
+	keyName, keyValue := "sessionKey", []byte("session value")
+	if err := jsSessionStorage.Set(keyName, keyValue); err != nil {
+		t.Fatalf("Failed to set %q: %+v", keyName, err)
+	}
+
+	if loaded, err := jsSessionStorage.Get(keyName); err != nil {
+		t.Errorf("Failed to load %q: %+v", keyName, err)
+	} else if !bytes.Equal(keyValue, loaded) {
+		t.Errorf("Loaded value does not match original for %q"+
+			"\nexpected: %q\nreceived: %q", keyName, keyValue, loaded)
+	}
+
+	if _, err := jsStorage.Get(keyName); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Key %q set in session storage found in local storage: %v",
+			keyName, err)
+	}
+}
+
+// Tests that NewStorage works with a mock Storage object and prefixes the keys
+// it creates.
+func TestNewStorage(t *testing.T) {
+	mock := js.Global().Get("Function").New(`
+		class MockStorage {
+			map = new Map()
+			getItem(k) { return this.map.has(k) ? this.map.get(k) : null }
+			setItem(k, v) { this.map.set(k, String(v)) }
+			removeItem(k) { this.map.delete(k) }
+			clear() { this.map.clear() }
+			key(n) { const keys = [...this.map.keys()]; return n < keys.length ? keys[n] : null }
+			get length() { return this.map.size }
+		}
+		return new MockStorage()`).Invoke()
+
+	s := NewStorage(mock, "prefix/")
+	keyName, keyValue := "key", []byte("value")
+	if err := s.Set(keyName, keyValue); err != nil {
+		t.Fatalf("Failed to set %q: %+v", keyName, err)
+	}
+
+	if loaded, err := s.Get(keyName); err != nil {
+		t.Errorf("Failed to load %q: %+v", keyName, err)
+	} else if !bytes.Equal(keyValue, loaded) {
+		t.Errorf("Loaded value does not match original for %q"+
+			"\nexpected: %q\nreceived: %q", keyName, keyValue, loaded)
+	}
+
+	if !mock.Get("map").Call("has", "prefix/"+keyName).Bool() {
+		t.Errorf("Key %q not set with prefix in mock storage.", keyName)
+	}
+	if keys := s.Keys(); len(keys) != 1 || keys[0] != keyName {
+		t.Errorf("Unexpected keys.\nexpected: %q\nreceived: %q",
+			[]string{keyName}, keys)
+	}
+
+	if n := s.Clear(); n != 1 || s.Length() != 0 {
+		t.Errorf("Failed to clear mock storage. Cleared %d, %d remaining.",
+			n, s.Length())
+	}
+}
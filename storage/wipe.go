@@ -0,0 +1,270 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"strings"
+	"syscall/js"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/elixxir/wasm-utils/exception"
+	"gitlab.com/elixxir/wasm-utils/utils"
+)
+
+// WipeOptions controls which data Wipe removes and how.
+type WipeOptions struct {
+	// Overwrite replaces every value with filler of the same length before
+	// removing it. This is only supported for localStorage and
+	// sessionStorage; IndexedDB databases and caches are deleted as a whole.
+	Overwrite bool
+
+	// IndexedDBPrefix is the name prefix of the IndexedDB databases to delete.
+	// This package does not create databases, so the prefix must be the one
+	// used by the application. If empty, no databases are deleted.
+	IndexedDBPrefix string
+
+	// CachePrefix is the name prefix of the Cache API caches to delete. This
+	// package does not create caches, so the prefix must be the one used by
+	// the application. If empty, no caches are deleted.
+	CachePrefix string
+}
+
+// ErrDatabaseBlocked is returned when an IndexedDB database cannot be deleted
+// because the application still has an open connection to it. The database is
+// deleted once all connections are closed.
+var ErrDatabaseBlocked = errors.New("deletion blocked by an open connection")
+
+// WipeReport lists everything removed by Wipe.
+type WipeReport struct {
+	// LocalStorage is the list of key names removed from localStorage.
+	LocalStorage []string
+
+	// SessionStorage is the list of key names removed from sessionStorage.
+	SessionStorage []string
+
+	// IndexedDB is the list of IndexedDB database names deleted.
+	IndexedDB []string
+
+	// Caches is the list of Cache API cache names deleted.
+	Caches []string
+}
+
+// Wipe removes all data created by this WASM binary from localStorage,
+// sessionStorage, IndexedDB, and the Cache API and returns a report of what was
+// removed. Backends not available in the current scope are skipped, as are
+// IndexedDB and the Cache API if their prefix is not set in the options.
+//
+// Wipe attempts to remove everything even if some removals fail. The report
+// contains everything that was removed and the returned error describes each
+// failure.
+func Wipe(opts WipeOptions) (WipeReport, error) {
+	var report WipeReport
+	var errs []string
+
+	if v := js.Global().Get("localStorage"); v.Truthy() {
+		report.LocalStorage = wipeStorage(&LocalStorageJS{v}, opts.Overwrite)
+	}
+	if v := js.Global().Get("sessionStorage"); v.Truthy() {
+		report.SessionStorage = wipeStorage(&LocalStorageJS{v}, opts.Overwrite)
+	}
+
+	var err error
+	if opts.IndexedDBPrefix != "" {
+		report.IndexedDB, err = wipeIndexedDB(opts.IndexedDBPrefix)
+		if err != nil {
+			errs = append(errs, "IndexedDB: "+err.Error())
+		}
+	}
+	if opts.CachePrefix != "" {
+		report.Caches, err = wipeCaches(opts.CachePrefix)
+		if err != nil {
+			errs = append(errs, "Cache API: "+err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return report, errors.Errorf(
+			"failed to wipe all data: %s", strings.Join(errs, "; "))
+	}
+	return report, nil
+}
+
+// wipeStorage removes every key with the WASM prefix from the Storage object,
+// overwriting each value first if requested. Returns the removed key names
+// without the prefix.
+func wipeStorage(s *LocalStorageJS, overwrite bool) []string {
+	keys := s.KeysPrefix(localStorageWasmPrefix)
+	for _, keyName := range keys {
+		if overwrite {
+			value, err := s.GetItem(localStorageWasmPrefix + keyName)
+			if err == nil {
+				n := len(utf16.Encode([]rune(value)))
+				_ = s.SetItem(
+					localStorageWasmPrefix+keyName, strings.Repeat("0", n))
+			}
+		}
+		s.RemoveItem(localStorageWasmPrefix + keyName)
+	}
+	return keys
+}
+
+// wipeIndexedDB deletes all IndexedDB databases with the given name prefix.
+// Returns the names of the deleted databases. Databases with an open connection
+// are not deleted and are reported with ErrDatabaseBlocked. Does nothing if
+// IndexedDB or listing databases is not supported.
+func wipeIndexedDB(prefix string) ([]string, error) {
+	idb := js.Global().Get("indexedDB")
+	if !idb.Truthy() || idb.Get("databases").Type() != js.TypeFunction {
+		return nil, nil
+	}
+
+	dbs, err := await(func() js.Value { return idb.Call("databases") })
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list databases")
+	}
+
+	var deleted, errs []string
+	for i := 0; i < dbs.Length(); i++ {
+		name := dbs.Index(i).Get("name").String()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		request, err := exception.RunAndCatch(
+			func() js.Value { return idb.Call("deleteDatabase", name) })
+		if err == nil {
+			_, err = awaitRequest(request)
+		}
+		if err != nil {
+			errs = append(errs, name+": "+err.Error())
+		} else {
+			deleted = append(deleted, name)
+		}
+	}
+
+	if len(errs) > 0 {
+		return deleted, errors.Errorf(
+			"failed to delete databases: %s", strings.Join(errs, "; "))
+	}
+	return deleted, nil
+}
+
+// wipeCaches deletes all Cache API caches with the given name prefix. Returns
+// the names of the deleted caches. Does nothing if the Cache API is not
+// supported.
+func wipeCaches(prefix string) ([]string, error) {
+	caches := js.Global().Get("caches")
+	if !caches.Truthy() {
+		return nil, nil
+	}
+
+	names, err := await(func() js.Value { return caches.Call("keys") })
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list caches")
+	}
+
+	var deleted, errs []string
+	for i := 0; i < names.Length(); i++ {
+		name := names.Index(i).String()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		_, err = await(func() js.Value { return caches.Call("delete", name) })
+		if err != nil {
+			errs = append(errs, name+": "+err.Error())
+		} else {
+			deleted = append(deleted, name)
+		}
+	}
+
+	if len(errs) > 0 {
+		return deleted, errors.Errorf(
+			"failed to delete caches: %s", strings.Join(errs, "; "))
+	}
+	return deleted, nil
+}
+
+// awaitRequest waits for the IDBRequest to complete. Returns the request's
+// result on success, its error on failure, or ErrDatabaseBlocked if it is
+// blocked by an open connection. A blocked request stays pending and
+// completes once the connections are closed.
+func awaitRequest(request js.Value) (js.Value, error) {
+	if err := exception.CheckBlocking("storage.Wipe"); err != nil {
+		return js.Undefined(), err
+	}
+
+	type result struct {
+		value js.Value
+		err   error
+	}
+	results := make(chan result, 1)
+	send := func(r result) {
+		select {
+		case results <- r:
+		default:
+		}
+	}
+
+	handlers := map[string]js.Func{
+		"onsuccess": js.FuncOf(func(js.Value, []js.Value) any {
+			send(result{value: request.Get("result")})
+			return nil
+		}),
+		"onerror": js.FuncOf(func(js.Value, []js.Value) any {
+			send(result{err: js.Error{Value: request.Get("error")}})
+			return nil
+		}),
+		"onblocked": js.FuncOf(func(js.Value, []js.Value) any {
+			send(result{err: ErrDatabaseBlocked})
+			return nil
+		}),
+	}
+	for event, fn := range handlers {
+		request.Set(event, fn)
+	}
+	defer func() {
+		for event, fn := range handlers {
+			request.Set(event, js.Null())
+			fn.Release()
+		}
+	}()
+
+	r := <-results
+	return r.value, r.err
+}
+
+// await calls the function that returns a promise and waits for it to settle.
+// Returns the resolved value or the rejection or exception as an error.
+// Returns exception.ErrEventLoopBlocked if called on the Javascript event loop.
+func await(fn func() js.Value) (js.Value, error) {
+	if err := exception.CheckBlocking("storage.Wipe"); err != nil {
+		return js.Undefined(), err
+	}
+
+	promise, err := exception.RunAndCatch(fn)
+	if err != nil {
+		return js.Undefined(), err
+	}
+
+	result, rejection := utils.Await(promise)
+	if rejection != nil {
+		if len(rejection) > 0 {
+			return js.Undefined(), js.Error{Value: rejection[0]}
+		}
+		return js.Undefined(), errors.New("promise rejected")
+	}
+	if len(result) > 0 {
+		return result[0], nil
+	}
+	return js.Undefined(), nil
+}
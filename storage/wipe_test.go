@@ -0,0 +1,157 @@
+////////////////////////////////////////////////////////////////////////////////
+// Copyright © 2022 xx foundation                                             //
+//                                                                            //
+// Use of this source code is governed by a license that can be found in the  //
+// LICENSE file.                                                              //
+////////////////////////////////////////////////////////////////////////////////
+
+//go:build js && wasm
+
+package storage
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+// Tests that Wipe removes all WASM keys from localStorage and sessionStorage,
+// reports them, and does not remove any other keys.
+func TestWipe(t *testing.T) {
+	local := &LocalStorageJS{js.Global().Get("localStorage")}
+	session := &LocalStorageJS{js.Global().Get("sessionStorage")}
+	local.Clear()
+	session.Clear()
+
+	for _, s := range []*LocalStorageJS{local, session} {
+		for _, keyName := range []string{"key1", "key2"} {
+			err := s.SetItem(localStorageWasmPrefix+keyName, "value")
+			if err != nil {
+				t.Errorf("Failed to set %q: %+v", keyName, err)
+			}
+		}
+		if err := s.SetItem("other", "value"); err != nil {
+			t.Errorf("Failed to set %q: %+v", "other", err)
+		}
+	}
+
+	report, err := Wipe(WipeOptions{Overwrite: true})
+	if err != nil {
+		t.Errorf("Failed to wipe: %+v", err)
+	}
+
+	expected := []string{"key1", "key2"}
+	sort.Strings(report.LocalStorage)
+	sort.Strings(report.SessionStorage)
+	if !reflect.DeepEqual(expected, report.LocalStorage) {
+		t.Errorf("Unexpected localStorage keys removed."+
+			"\nexpected: %q\nreceived: %q", expected, report.LocalStorage)
+	}
+	if !reflect.DeepEqual(expected, report.SessionStorage) {
+		t.Errorf("Unexpected sessionStorage keys removed."+
+			"\nexpected: %q\nreceived: %q", expected, report.SessionStorage)
+	}
+
+	for _, s := range []*LocalStorageJS{local, session} {
+		if keys := s.Keys(); !reflect.DeepEqual([]string{"other"}, keys) {
+			t.Errorf("Unexpected keys remaining.\nexpected: %q\nreceived: %q",
+				[]string{"other"}, keys)
+		}
+	}
+}
+
+// newMockIndexedDB returns a mock of the IndexedDB factory with the databases.
+// Deleting a database in blocked fires the blocked event instead of success.
+// Deleted database names are added to its deleted array.
+func newMockIndexedDB(databases, blocked []any) js.Value {
+	return js.Global().Get("Function").New("names", "blocked", `
+		const idb = {deleted: []}
+		idb.databases = () => Promise.resolve(names.map(name => ({name})))
+		idb.deleteDatabase = (name) => {
+			const request = {}
+			setTimeout(() => {
+				if (blocked.includes(name)) {
+					request.onblocked && request.onblocked({})
+				} else {
+					idb.deleted.push(name)
+					request.onsuccess && request.onsuccess({})
+				}
+			})
+			return request
+		}
+		return idb`).Invoke(databases, blocked)
+}
+
+// newMockCaches returns a mock of the Cache API with the caches. Deleted cache
+// names are added to its deleted array.
+func newMockCaches(names []any) js.Value {
+	return js.Global().Get("Function").New("names", `
+		const caches = {deleted: []}
+		caches.keys = () => Promise.resolve(names)
+		caches.delete = (name) => {
+			caches.deleted.push(name)
+			return Promise.resolve(true)
+		}
+		return caches`).Invoke(names)
+}
+
+// jsStrings converts a Javascript array of strings to a sorted Go slice.
+func jsStrings(v js.Value) []string {
+	s := make([]string, v.Length())
+	for i := range s {
+		s[i] = v.Index(i).String()
+	}
+	sort.Strings(s)
+	return s
+}
+
+// Tests that Wipe deletes only the IndexedDB databases and caches with the
+// prefixes, reports a database blocked by an open connection as not deleted,
+// and deletes neither when no prefix is set.
+func TestWipe_IndexedDB_Caches(t *testing.T) {
+	idb := newMockIndexedDB(
+		[]any{"app-db1", "app-db2", "app-open", "other"}, []any{"app-open"})
+	caches := newMockCaches([]any{"app-cache", "other"})
+	js.Global().Set("indexedDB", idb)
+	js.Global().Set("caches", caches)
+	defer js.Global().Delete("indexedDB")
+	defer js.Global().Delete("caches")
+
+	report, err := Wipe(WipeOptions{})
+	if err != nil {
+		t.Errorf("Failed to wipe without prefixes: %+v", err)
+	}
+	if report.IndexedDB != nil || report.Caches != nil ||
+		idb.Get("deleted").Length() != 0 || caches.Get("deleted").Length() != 0 {
+		t.Errorf("Databases or caches deleted without prefixes: %+v", report)
+	}
+
+	report, err = Wipe(WipeOptions{IndexedDBPrefix: "app-", CachePrefix: "app-"})
+	if err == nil || !strings.Contains(err.Error(), ErrDatabaseBlocked.Error()) {
+		t.Errorf("Blocked database not reported.\nexpected: %v\nreceived: %v",
+			ErrDatabaseBlocked, err)
+	}
+
+	expected := []string{"app-db1", "app-db2"}
+	sort.Strings(report.IndexedDB)
+	if !reflect.DeepEqual(expected, report.IndexedDB) {
+		t.Errorf("Unexpected databases reported."+
+			"\nexpected: %q\nreceived: %q", expected, report.IndexedDB)
+	}
+	if deleted := jsStrings(idb.Get("deleted")); !reflect.DeepEqual(expected, deleted) {
+		t.Errorf("Unexpected databases deleted."+
+			"\nexpected: %q\nreceived: %q", expected, deleted)
+	}
+
+	expected = []string{"app-cache"}
+	if !reflect.DeepEqual(expected, report.Caches) {
+		t.Errorf("Unexpected caches reported."+
+			"\nexpected: %q\nreceived: %q", expected, report.Caches)
+	}
+	if deleted := jsStrings(caches.Get("deleted")); !reflect.DeepEqual(expected, deleted) {
+		t.Errorf("Unexpected caches deleted."+
+			"\nexpected: %q\nreceived: %q", expected, deleted)
+	}
+}
@@ -12,6 +12,7 @@ package utils
 import (
 	"github.com/pkg/errors"
 	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/wasm-utils/exception"
 	"syscall/js"
 )
 
@@ -50,11 +51,15 @@ func WrapCB(parent js.Value, m string) func(args ...any) js.Value {
 // PromiseFn converts the Javascript Promise construct into Go.
 //
 // Call resolve with the return of the function on success. Call reject with an
-// error on failure.
+// error on failure. Any Go error passed to reject is converted to a Javascript
+// Error using [exception.NewError].
 type PromiseFn func(resolve, reject func(args ...any) js.Value)
 
 // CreatePromise creates a Javascript promise to return the value of a blocking
 // Go function to Javascript.
+//
+// If the function panics, the panic is recovered, reported with
+// [exception.Report], and the promise is rejected with the error.
 func CreatePromise(f PromiseFn) any {
 	// Create handler for promise (this will be a Javascript function)
 	var handler js.Func
@@ -62,7 +67,13 @@ func CreatePromise(f PromiseFn) any {
 		// Spawn a new go routine to perform the blocking function
 		go func(resolve, reject js.Value) {
 			go handler.Release()
-			f(resolve.Invoke, reject.Invoke)
+			defer exception.CatchHandler(func(err error) {
+				exception.Report(err)
+				reject.Invoke(convertErrors([]any{err})...)
+			})
+			f(resolve.Invoke, func(args ...any) js.Value {
+				return reject.Invoke(convertErrors(args)...)
+			})
 		}(args[0], args[1])
 
 		return nil
@@ -72,11 +83,32 @@ func CreatePromise(f PromiseFn) any {
 	return Promise.New(handler)
 }
 
+// convertErrors replaces each Go error in the arguments with a Javascript
+// Error using exception.NewError. Javascript exceptions, such as those
+// returned by exception.Catch, are replaced with the value that was thrown.
+func convertErrors(args []any) []any {
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			args[i] = exception.NewError(err)
+		}
+	}
+	return args
+}
+
 // Await waits on a Javascript value. It blocks until the awaitable successfully
 // resolves to the result or rejects to err.
 //
 // If there is a result, err will be nil and vice versa.
+//
+// Await must not be called on the Javascript event loop (i.e., directly inside
+// a js.FuncOf), where it would deadlock the runtime. Instead, err contains a
+// Javascript Error created from [exception.ErrEventLoopBlocked].
 func Await(awaitable js.Value) (result []js.Value, err []js.Value) {
+	if blockErr := exception.CheckBlocking("utils.Await"); blockErr != nil {
+		jww.ERROR.Printf("%+v", errors.WithStack(blockErr))
+		return nil, []js.Value{exception.NewError(blockErr)}
+	}
+
 	then := make(chan []js.Value)
 	defer close(then)
 	thenFunc := js.FuncOf(func(this js.Value, args []js.Value) any {
@@ -92,6 +92,41 @@
 	const encoder = new TextEncoder("utf-8");
 	const decoder = new TextDecoder("utf-8");
 
+	// Throws its argument. Used by gitlab.com/elixxir/wasm-utils/exception to
+	// throw exceptions without the Function constructor, which is blocked by a
+	// Content-Security-Policy without 'unsafe-eval'.
+	globalThis.wasmUtilsThrow = (value) => {
+		throw value;
+	};
+
+	// Wraps Go functions so that the exceptions they return, marked with mark,
+	// are thrown. Used by gitlab.com/elixxir/wasm-utils/exception.FuncOf.
+	// Defines a subclass of Base with the name and properties on its prototype.
+	// Used by gitlab.com/elixxir/wasm-utils/exception.RegisterClass so that the
+	// class does not depend on the Go program.
+	globalThis.wasmUtilsDefineClass = (Base, name, properties) => {
+		const C = class extends Base {};
+		Object.defineProperty(C, "name", {value: name});
+		Object.defineProperty(C.prototype, "name",
+			{value: name, writable: true, configurable: true});
+		Object.assign(C.prototype, properties);
+		return C;
+	};
+
+	globalThis.wasmUtilsFuncWrapper = (() => {
+		const marker = Symbol("throw");
+		return {
+			mark: (exception) => ({[marker]: true, exception: exception}),
+			wrap: (fn) => function (...args) {
+				const result = fn.apply(this, args);
+				if (result !== null && typeof result === "object" && result[marker]) {
+					throw result.exception;
+				}
+				return result;
+			},
+		};
+	})();
+
 	globalThis.Go = class {
 		constructor() {
 			this.argv = ["js"];
@@ -451,13 +486,6 @@
 					"debug": (value) => {
 						console.log(value);
 					},
-
-					// func Throw(exception string, message string)
-					'gitlab.com/elixxir/wasm-utils/exception.throw': (sp) => {
-						const exception = loadString(sp + 8)
-						const message = loadString(sp + 24)
-						throw globalThis[exception](message)
-					},
 				}
 			};
 		}